@@ -1,30 +1,39 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
-	"net"
-	"path/filepath"
-
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
-	ctx     context.Context
-	Version string
+	ctx      context.Context
+	Version  string
+	runs     *runManager
+	settings settingsStore
+
+	profiles    []Profile // Built-in and configured profiles, loaded at startup
+	profileRuns profileRuns
+
+	services serviceKB // Built-in and configured service knowledge base, loaded at startup
+
+	// startupLog holds messages produced before the frontend is ready
+	startupLog []string
 }
 
 // NewApp creates a new App application struct
 func NewApp(version string) *App {
 	return &App{
 		Version: "V.0.1.33a",
+		runs:    newRunManager(),
 	}
 }
 
@@ -37,9 +46,26 @@ func (a *App) GetAppVersion() string {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+
+	a.startupLog = append(a.startupLog, a.loadServices()...)
+	a.startupLog = append(a.startupLog, a.loadCustomChecks()...)
+	// After the custom checks, whose timeouts the settings may override
+	if err := a.loadSettings(); err != nil {
+		a.startupLog = append(a.startupLog, fmt.Sprintf("[WARN] Settings not loaded, using defaults: %s", err))
+	}
+	a.startupLog = append(a.startupLog, a.loadProfiles()...)
 }
 
-// ExportLogs opens a save dialog and saves the provided content to a file
+// domReady is called once the frontend has loaded and can receive events
+func (a *App) domReady(ctx context.Context) {
+	for _, msg := range a.startupLog {
+		wailsRuntime.EventsEmit(ctx, "log", msg)
+	}
+	a.startupLog = nil
+}
+
+// ExportLogs opens a save dialog and saves the provided content to a file,
+// with the session's run results alongside it as JSON
 func (a *App) ExportLogs(content string) error {
 	defaultName := fmt.Sprintf("checkpoint-log-%s.txt", time.Now().Format("20060102-150405"))
 
@@ -64,7 +90,35 @@ func (a *App) ExportLogs(content string) error {
 		return nil // User cancelled
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	// Structured results (including check data such as the interface
+	// inventory) go next to the text log
+	return a.exportResults(strings.TrimSuffix(path, filepath.Ext(path)) + ".json")
+}
+
+// resultsExport is the layout of the JSON file written by ExportLogs.
+type resultsExport struct {
+	Version  string      `json:"version"`
+	Platform string      `json:"platform"`
+	Exported time.Time   `json:"exported"`
+	Results  []RunResult `json:"results"`
+}
+
+// exportResults writes this session's run results as JSON.
+func (a *App) exportResults(path string) error {
+	data, err := json.MarshalIndent(resultsExport{
+		Version:  a.Version,
+		Platform: platformName(runtime.GOOS),
+		Exported: time.Now(),
+		Results:  a.GetRunResults(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
 }
 
 // getAppBaseDir returns the directory where the application is running.
@@ -107,19 +161,19 @@ func (a *App) getAppBaseDir() (string, error) {
 }
 
 // captureScreenshot takes a screenshot silently and saves it to a timestamped folder
-func (a *App) captureScreenshot(reason string) {
+func (a *App) captureScreenshot(reason string, logFunc func(string)) {
 	// 1. Disable entirely on macOS
 	if runtime.GOOS == "darwin" {
 		return
 	}
 
-	// 2. Delayed Capture (Windows) - Wait for window to be visible
+	// 2. Delayed Capture - Wait for window to be visible
 	time.Sleep(2 * time.Second)
 
 	// Root screenshot directory relative to executable (Portable)
 	baseDir, err := a.getAppBaseDir()
 	if err != nil {
-		wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[ERR] Failed to determine app location: %s. Using temp dir.", err))
+		logFunc(fmt.Sprintf("[ERR] Failed to determine app location: %s. Using temp dir.", err))
 		baseDir = os.TempDir()
 	}
 
@@ -129,11 +183,11 @@ func (a *App) captureScreenshot(reason string) {
 
 	// Attempt to create directory. If read-only (e.g. CD-ROM), fallback to temp.
 	if err := os.MkdirAll(screenshotDir, 0755); err != nil {
-		wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[WARN] Cannot write to %s (Read-only?). Falling back to Temp.", screenshotDir))
+		logFunc(fmt.Sprintf("[WARN] Cannot write to %s (Read-only?). Falling back to Temp.", screenshotDir))
 		baseDir = os.TempDir()
 		screenshotDir = filepath.Join(baseDir, dateFolder)
 		if err := os.MkdirAll(screenshotDir, 0755); err != nil {
-			wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[ERR] Failed to create temp screenshot dir: %s", err))
+			logFunc(fmt.Sprintf("[ERR] Failed to create temp screenshot dir: %s", err))
 			return
 		}
 	}
@@ -144,467 +198,18 @@ func (a *App) captureScreenshot(reason string) {
 	filename := fmt.Sprintf("%03d.png", count) // 001.png
 	fullPath := filepath.Join(screenshotDir, filename)
 
-	wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[INFO] Capturing screenshot for '%s'...", reason))
-	wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[INFO] Saving to: %s", fullPath))
+	logFunc(fmt.Sprintf("[INFO] Capturing screenshot for '%s'...", reason))
+	logFunc(fmt.Sprintf("[INFO] Saving to: %s", fullPath))
 
-	var cmd *exec.Cmd
-	if runtime.GOOS == "darwin" {
-		// macOS: screencapture -x (silent) -m (main monitor)
-		cmd = exec.Command("screencapture", "-x", "-m", fullPath)
-	} else {
-		// Windows: Powershell snippet to capture screen
-		// NOTE: This requires .NET (System.Windows.Forms).
-		psScript := fmt.Sprintf(`
-Add-Type -AssemblyName System.Windows.Forms
-Add-Type -AssemblyName System.Drawing
-$Screen = [System.Windows.Forms.Screen]::PrimaryScreen
-$Width = $Screen.Bounds.Width
-$Height = $Screen.Bounds.Height
-$Left = $Screen.Bounds.Left
-$Top = $Screen.Bounds.Top
-$Bitmap = New-Object System.Drawing.Bitmap $Width, $Height
-$Graphic = [System.Drawing.Graphics]::FromImage($Bitmap)
-$Graphic.CopyFromScreen($Left, $Top, 0, 0, $Bitmap.Size)
-$Bitmap.Save('%s')
-$Graphic.Dispose()
-$Bitmap.Dispose()
-`, fullPath)
-		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", psScript)
-		cmd.SysProcAttr = getSysProcAttr()
+	cmd, err := screenshotCommand(fullPath)
+	if err != nil {
+		logFunc(fmt.Sprintf("[WARN] Screenshot skipped: %s", err))
+		return
 	}
 
 	if err := cmd.Run(); err != nil {
-		wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("[ERR] Screenshot failed: %s", err))
+		logFunc(fmt.Sprintf("[ERR] Screenshot failed: %s", err))
 	} else {
-		wailsRuntime.EventsEmit(a.ctx, "log", "[OK] Screenshot saved.")
-	}
-}
-
-// ExecuteCommand runs a system command based on the feature name
-func (a *App) ExecuteCommand(feature string) string {
-
-	// Helper to emit log lines
-	emitLog := func(msg string) {
-		wailsRuntime.EventsEmit(a.ctx, "log", msg)
-	}
-
-	// Determine Platform
-	isMac := runtime.GOOS == "darwin"
-	isWindows := runtime.GOOS == "windows"
-
-	// Helper to run command and stream output
-	streamCommand := func(command string, args ...string) {
-		go func() {
-			startTime := time.Now()
-
-			// Formatted Header
-			separator := "====================================="
-			header := fmt.Sprintf("%s\n[ %s ]\nTime : %s\nStatus : Running...\n%s",
-				separator, feature, startTime.Format("2006-01-02 15:04:05"), separator)
-			emitLog(header)
-
-			cmd := exec.Command(command, args...)
-
-			// SILENT EXECUTION FOR WINDOWS
-			// SILENT EXECUTION FOR WINDOWS
-			if isWindows {
-				cmd.SysProcAttr = getSysProcAttr()
-			}
-
-			// Setup pipes
-			stdout, err := cmd.StdoutPipe()
-			if err != nil {
-				emitLog(fmt.Sprintf("[ERROR] Failed to get stdout pipeline: %s", err))
-				wailsRuntime.EventsEmit(a.ctx, "done", feature)
-				return
-			}
-			stderr, err := cmd.StderrPipe()
-			if err != nil {
-				emitLog(fmt.Sprintf("[ERROR] Failed to get stderr pipeline: %s", err))
-				wailsRuntime.EventsEmit(a.ctx, "done", feature)
-				return
-			}
-
-			if err := cmd.Start(); err != nil {
-				emitLog(fmt.Sprintf("[ERROR] Failed to start command: %s", err))
-				wailsRuntime.EventsEmit(a.ctx, "done", feature)
-				return
-			}
-
-			// Read logs concurrently
-			doneReading := make(chan bool)
-
-			// Stdout reader
-			go func() {
-				scanner := bufio.NewScanner(stdout)
-				for scanner.Scan() {
-					emitLog(scanner.Text())
-				}
-				doneReading <- true
-			}()
-
-			// Stderr reader
-			go func() {
-				scanner := bufio.NewScanner(stderr)
-				for scanner.Scan() {
-					emitLog(fmt.Sprintf("[ERR] %s", scanner.Text()))
-				}
-				doneReading <- true
-			}()
-
-			// Wait for process
-			err = cmd.Wait()
-
-			// Wait for readers to drain
-			<-doneReading
-			<-doneReading
-
-			if err != nil {
-				emitLog(fmt.Sprintf("\n[STOP] Process finished with error: %v", err))
-			} else {
-				emitLog("\n[OK] Process completed successfully.")
-			}
-
-			// SCREENSHOT TRIGGER: If error or specific conditions met
-			// The user requested:
-			// 1. Specific popup window appears (hard to detect without keeping process open, but we can infer from "Open/Start" commands)
-			// 2. Command executes outside terminal (e.g. "open" / "start")
-			// 3. Fails to produce output (stderr) - covered by err check above
-
-			triggerScreenshot := false
-			if err != nil {
-				triggerScreenshot = true
-			}
-
-			// Hardcoded list of features that open external windows
-			if feature == "System Information" || feature == "Task Manager" || feature == "Registry Editor" || feature == "Device Manager (Bluetooth)" || feature == "Remote Access Settings" {
-				triggerScreenshot = true
-			}
-
-			if triggerScreenshot {
-				a.captureScreenshot(feature)
-			}
-
-			// Minimum delay for visible UX
-			elapsed := time.Since(startTime)
-			if elapsed < 700*time.Millisecond {
-				time.Sleep(700*time.Millisecond - elapsed)
-			}
-
-			// Signal done
-			wailsRuntime.EventsEmit(a.ctx, "done", feature)
-		}()
+		logFunc("[OK] Screenshot saved.")
 	}
-
-	// Helper to run PowerShell command securely and silently
-	runPowerShell := func(psCommand string) {
-		// -NoProfile: No user profile loaded
-		// -NonInteractive: No prompt
-		// -NoLogo: Hides version banner (though we are capturing output anyway)
-		// -Command: The actual code
-		streamCommand("powershell", "-NoProfile", "-NonInteractive", "-NoLogo", "-Command", psCommand)
-	}
-
-	switch feature {
-	// --- NETWORK ---
-	case "Cek IP": // ipconfig /all
-		if isMac {
-			streamCommand("ifconfig")
-		} else {
-			streamCommand("ipconfig", "/all")
-		}
-
-	case "Cek Routing": // tracert
-		if isMac {
-			streamCommand("netstat", "-nr")
-		} else {
-			streamCommand("tracert", "8.8.8.8")
-		}
-
-	case "Netstat": // netstat -a
-		streamCommand("netstat", "-a")
-
-	case "ARP Table": // arp -a -v
-		streamCommand("arp", "-a")
-
-	case "Ping Connectivity": // ping 8.8.8.8
-		if isMac {
-			streamCommand("ping", "-c", "4", "8.8.8.8")
-		} else {
-			streamCommand("ping", "8.8.8.8")
-		}
-
-	// --- APPLICATION / SYSTEM ---
-	case "System Information": // logic moved from Remote System Properties
-		if isMac {
-			streamCommand("system_profiler", "SPSoftwareDataType")
-			streamCommand("open", "/System/Library/PreferencePanes/Dock.prefPane") // Using Dock as generic placeholder or System Settings
-		} else {
-			runPowerShell("Get-ComputerInfo | Select-Object CsName, OsName, WindowsVersion, OsArchitecture, BiosVersion | Format-List")
-			runPowerShell("Start-Process ms-settings:about")
-		}
-
-	case "Check installed applications":
-		if isMac {
-			streamCommand("system_profiler", "SPApplicationsDataType")
-		} else {
-			runPowerShell("Get-WmiObject -Class Win32_Product | Select-Object Name, Version, Vendor, InstallDate | Format-Table -AutoSize")
-		}
-
-	case "List PS Drives":
-		if isMac {
-			streamCommand("df", "-h")
-		} else {
-			runPowerShell("Get-PSDrive | Format-Table -AutoSize")
-		}
-
-	case "Access HKLM Registry":
-		if isMac {
-			streamCommand("defaults", "read", "NSGlobalDomain")
-		} else {
-			runPowerShell("Get-ItemProperty HKLM:\\Software\\Microsoft\\Windows\\CurrentVersion | Select-Object -Property ProgramFilesDir, CommonFilesDir, DevicePath")
-		}
-
-	case "Startup Services": // Moved from Remote Services
-		if isMac {
-			streamCommand("ls", "-la", "/Library/LaunchDaemons")
-		} else {
-			runPowerShell("Get-CimInstance Win32_Service | Where-Object StartMode -eq 'Auto' | Select-Object Name, State, StartMode, PathName | Format-Table -AutoSize")
-		}
-
-	case "Registry Check": // Renamed from Startup Registry Check
-		if isMac {
-			streamCommand("ls", "-la", "/Library/LaunchAgents")
-		} else {
-			runPowerShell("Get-CimInstance Win32_StartupCommand | Select-Object Name, Command, Location | Format-Table -AutoSize")
-		}
-
-	case "Registry Editor": // Moved
-		if isMac {
-			streamCommand("open", "/Library/Preferences")
-		} else {
-			runPowerShell("Write-Output 'Registry Editor cannot be run silently. Please use system tools if GUI access is needed.'")
-			runPowerShell("Start-Process regedit") // Actually open it as per request "moved... must display info... also open" logic applied to System Info, but implied for Editor too
-		}
-
-	case "Task Manager": // Moved
-		if isMac {
-			streamCommand("open", "-a", "Activity Monitor")
-		} else {
-			runPowerShell("Get-Process | Sort-Object CPU -Descending | Select-Object -First 20 | Format-Table -AutoSize")
-			runPowerShell("Start-Process taskmgr")
-		}
-
-	// --- MALWARE / ANTI VIRUS ---
-	// --- MALWARE / ANTI VIRUS ---
-	case "Security Status":
-		if isMac {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : macOS\nStatus  : Checking Security Status...\n=====================================", feature))
-
-			// 3. Gatekeeper status
-			emitLog("[ Gatekeeper Status ]")
-			streamCommand("spctl", "--status")
-
-			// 4. SIP (System Integrity Protection)
-			emitLog("\n[ System Integrity Protection (SIP) ]")
-			streamCommand("csrutil", "status")
-
-		} else {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : Windows\nEngine  : Microsoft Defender\nStatus  : Checking Security Status...\n=====================================", feature))
-
-			// AntivirusEnabled, AMServiceEnabled
-			runPowerShell("Get-MpComputerStatus | Select-Object -Property AntivirusEnabled,AMServiceEnabled,AntispywareEnabled | Format-List")
-
-			// Detect third-party
-			runPowerShell("Get-CimInstance -Namespace root/SecurityCenter2 -ClassName AntivirusProduct | Select-Object -Property displayName,productState | Format-List")
-		}
-
-	case "Protection Health":
-		if isMac {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : macOS\nStatus  : Checking Protection Health...\n=====================================", feature))
-
-			// 1. XProtect status
-			emitLog("[ XProtect Status ]")
-			streamCommand("bash", "-c", "defaults read /System/Library/CoreServices/XProtect.bundle/Contents/Info.plist CFBundleShortVersionString")
-
-			// 2. MRT (Malware Removal Tool)
-			emitLog("\n[ MRT Status ]")
-			streamCommand("bash", "-c", "if [ -d \"/System/Library/CoreServices/MRT.app\" ]; then defaults read /System/Library/CoreServices/MRT.app/Contents/Info.plist CFBundleShortVersionString; else echo \"MRT not found\"; fi")
-
-		} else {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : Windows\nEngine  : Microsoft Defender\nStatus  : Checking Protection Health...\n=====================================", feature))
-
-			// RealTimeProtection, Updates, etc.
-			runPowerShell("Get-MpComputerStatus | Select-Object -Property RealTimeProtectionEnabled,BehaviorMonitorEnabled,IoavProtectionEnabled,NISEnabled,AntivirusSignatureLastUpdated,QuickScanAge,FullScanAge | Format-List")
-		}
-
-	case "Run Quick Scan":
-		if isMac {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : macOS\nStatus  : Persistence Inspection (Passive)\n=====================================", feature))
-
-			// 5. Optional passive inspection
-			emitLog("[INFO] Scanning LaunchAgents and LaunchDaemons for persistence indicators...")
-			emitLog("[ LaunchAgents ]")
-			streamCommand("ls", "-la", "/Library/LaunchAgents")
-			streamCommand("ls", "-la", os.Getenv("HOME")+"/Library/LaunchAgents")
-
-			emitLog("\n[ LaunchDaemons ]")
-			streamCommand("ls", "-la", "/Library/LaunchDaemons")
-
-		} else {
-			emitLog(fmt.Sprintf("=====================================\n[ %s ]\nOS      : Windows\nEngine  : Microsoft Defender\nStatus  : Initiating Quick Scan...\n=====================================", feature))
-
-			emitLog("[WARN] This will start a Windows Defender Quick Scan.")
-			// Trigger scan and stream output (Note: Start-MpScan might output to host if not job)
-			runPowerShell("Start-MpScan -ScanType QuickScan | Out-String")
-		}
-
-	// --- REMOTE SERVICES ---
-	case "Check active network service ports":
-		emitLog(fmt.Sprintf("=====================================\n[ %s ]\nStatus : Checking Ports...\n=====================================", feature))
-		ports := map[string]string{
-			"21":   "FTP",
-			"22":   "SSH",
-			"445":  "SMB",
-			"3389": "RDP",
-		}
-
-		found := false
-		for port, name := range ports {
-			timeout := 500 * time.Millisecond
-			conn, err := net.DialTimeout("tcp", "127.0.0.1:"+port, timeout)
-			status := "CLOSED"
-			if err == nil {
-				conn.Close()
-				status = "OPEN"
-				found = true
-			}
-			emitLog(fmt.Sprintf("[%s] Port %s (%s)", status, port, name))
-		}
-		if !found {
-			emitLog("[INFO] No active target services found locally.")
-		}
-		wailsRuntime.EventsEmit(a.ctx, "done", feature)
-
-	case "Check installed browser extensions":
-		emitLog(fmt.Sprintf("=====================================\n[ %s ]\nStatus : Checking Extensions...\n=====================================", feature))
-
-		if isWindows {
-			// Chrome
-			emitLog("\n[ Google Chrome ]")
-			chromePath := filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data", "Default", "Extensions")
-			runPowerShell(fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Depth 2 -ErrorAction SilentlyContinue | Select-Object FullName", chromePath))
-
-			// Edge
-			emitLog("\n[ Microsoft Edge ]")
-			edgePath := filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Edge", "User Data", "Default", "Extensions")
-			runPowerShell(fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Depth 2 -ErrorAction SilentlyContinue | Select-Object FullName", edgePath))
-
-			// Firefox
-			emitLog("\n[ Mozilla Firefox ]")
-			firefoxPath := filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox", "Profiles")
-			runPowerShell(fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Include 'extensions.json','addons.json' -ErrorAction SilentlyContinue | Select-Object FullName", firefoxPath))
-
-		} else {
-			// Chrome
-			emitLog("\n[ Google Chrome ]")
-			home, _ := os.UserHomeDir()
-			chromePath := filepath.Join(home, "Library", "Application Support", "Google", "Chrome", "Default", "Extensions")
-			streamCommand("ls", "-R", chromePath)
-
-			// Safari (Approximate - usually binary)
-			emitLog("\n[ Safari ]")
-			emitLog("[INFO] Safari Extensions are managed via App Store. Listing related .app bundles...")
-			streamCommand("mdfind", "kMDItemKind == 'Application' && kMDItemContentType == 'com.apple.safari.extension'")
-
-			// Firefox
-			emitLog("\n[ Mozilla Firefox ]")
-			firefoxPath := filepath.Join(home, "Library", "Application Support", "Firefox", "Profiles")
-			streamCommand("find", firefoxPath, "-name", "extensions.json")
-		}
-
-	case "Device Manager (Bluetooth)":
-		if isMac {
-			streamCommand("system_profiler", "SPBluetoothDataType")
-		} else {
-			runPowerShell("Get-PnpDevice -Class Bluetooth | Select-Object Status, Class, FriendlyName, InstanceId | Format-Table -AutoSize")
-		}
-
-	case "Open Remote Access Settings":
-		if isMac {
-			streamCommand("open", "/System/Library/PreferencePanes/SharingPref.prefPane")
-		} else {
-			runPowerShell("Start systempropertiesremote")
-		}
-
-	// --- CLEAN FILES ---
-	case "Run Full Cleanup":
-		a.performFullCleanup()
-
-	case "Open Temp Folder":
-		if isMac {
-			streamCommand("open", os.Getenv("TMPDIR"))
-		} else {
-			// Calculate size AND open folder
-			runPowerShell("Get-ChildItem -Path $env:TEMP -Recurse -Force -ErrorAction SilentlyContinue | Measure-Object -Property Length -Sum | Select-Object Count, @{Name='Total Size(MB)';Expression={[math]::round($_.Sum/1MB,2)}} | Format-List")
-			runPowerShell("Start-Process explorer $env:TEMP")
-		}
-
-	case "Open Trash / Recycle Bin":
-		if isMac {
-			streamCommand("open", os.Getenv("HOME")+"/.Trash")
-		} else {
-			// Calculate Recycle Bin size AND open
-			runPowerShell("Get-ChildItem 'C:\\$Recycle.Bin' -Recurse -Force -ErrorAction SilentlyContinue | Measure-Object -Property Length -Sum | Select-Object Count, @{Name='Total Size(MB)';Expression={[math]::round($_.Sum/1MB,2)}} | Format-List")
-			runPowerShell("Start-Process explorer shell:RecycleBinFolder")
-		}
-
-	case "Open Office Temp Files":
-		if isMac {
-			path := os.Getenv("HOME") + "/Library/Containers/com.microsoft.Word/Data/Library/Preferences/AutoRecovery"
-			streamCommand("open", path)
-		} else {
-			// Check common autorecover path AND open
-			wordPath := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Word")
-			runPowerShell(fmt.Sprintf("Get-ChildItem -Path '%s' -Filter *.asd -Recurse -ErrorAction SilentlyContinue | Select-Object Name, Length, LastWriteTime | Format-Table", wordPath))
-			runPowerShell(fmt.Sprintf("Start-Process explorer '%s'", wordPath))
-		}
-
-	default:
-		return fmt.Sprintf("Unknown feature: %s", feature)
-	}
-
-	return "Request received..."
-}
-
-func (a *App) performFullCleanup() {
-	// Helper to emit log lines
-	emitLog := func(msg string) {
-		wailsRuntime.EventsEmit(a.ctx, "log", msg)
-	}
-
-	startTime := time.Now()
-	separator := "====================================="
-	header := fmt.Sprintf("%s\n[ Run Full Cleanup ]\nTime : %s\nStatus : Running...\n%s",
-		separator, startTime.Format("2006-01-02 15:04:05"), separator)
-	emitLog(header)
-
-	isWindows := runtime.GOOS == "windows"
-	isMac := runtime.GOOS == "darwin"
-
-	if isWindows {
-		cleanupWindows(emitLog)
-	} else if isMac {
-		cleanupMac(emitLog)
-	} else {
-		emitLog("[ERROR] Unsupported platform for cleanup.")
-	}
-
-	elapsed := time.Since(startTime)
-	if elapsed < 700*time.Millisecond {
-		time.Sleep(700*time.Millisecond - elapsed)
-	}
-
-	emitLog("\n[OK] Cleanup process completed.")
-	wailsRuntime.EventsEmit(a.ctx, "done", "Run Full Cleanup")
 }
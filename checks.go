@@ -0,0 +1,510 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Built-in check definitions. Adding a check means adding one registerCheck
+// call here; the UI and ExecuteCommand pick it up from the registry. Linux
+// implementations that need Linux-only code are listed in linuxChecks
+// (checks_linux.go) instead.
+func init() {
+	// --- NETWORK ---
+	registerCheck(Check{
+		ID:          "cek-ip",
+		Name:        "Cek IP",
+		Category:    "network",
+		Description: "Lists network interfaces with MAC, MTU, state, IPv4/IPv6 addresses, gateway and DNS.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkInterfaces,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "cek-routing",
+		Name:        "Cek Routing",
+		Category:    "network",
+		Description: "Shows the routing table and flags missing or multiple default gateways and VPN/tunnel routes.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkRoutes,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "traceroute",
+		Name:        "Traceroute",
+		Category:    "network",
+		Description: "Traces the path to a configurable target (default 8.8.8.8) hop by hop, with round-trip times.",
+		Timeout:     5 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			platformAny: checkTraceroute,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "netstat",
+		Name:        "Netstat",
+		Category:    "network",
+		Description: "Lists all TCP/UDP sockets with their state and owning process.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkSockets(socketsAll),
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "listening-ports",
+		Name:        "Listening Ports",
+		Category:    "network",
+		Description: "Lists the listening TCP/UDP sockets and highlights those open on all interfaces.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkSockets(socketsListening),
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "established-connections",
+		Name:        "Established Connections",
+		Category:    "network",
+		Description: "Lists the established TCP connections with their owning process.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkSockets(socketsEstablished),
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "arp-table",
+		Name:        "ARP Table",
+		Category:    "network",
+		Description: "Shows the IP to MAC address mapping of the local network and flags signs of ARP spoofing.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkNeighbors,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "ping-connectivity",
+		Name:        "Ping Connectivity",
+		Category:    "network",
+		Description: "Runs the configured ping, DNS, TCP and HTTP probes and reports a pass/fail matrix.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkConnectivity,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "dns-config",
+		Name:        "DNS Configuration",
+		Category:    "network",
+		Description: "Audits the resolvers, hosts file and encrypted DNS settings, and flags resolvers outside the network or the allowlist.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkDNS,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "hosts-file",
+		Name:        "Hosts File Integrity",
+		Category:    "network",
+		Description: "Compares the hosts file with the baseline recorded on this machine and flags redirects of exam, education and security vendor domains.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkHostsFile,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "proxy-config",
+		Name:        "Proxy Configuration",
+		Category:    "network",
+		Description: "Collects proxy and PAC settings from the environment, the system and browser policies, and flags proxies on unknown hosts.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkProxy,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "wifi-profiles",
+		Name:        "Wi-Fi Profiles",
+		Category:    "network",
+		Description: "Lists saved wireless and network profiles and the connected SSID, and flags open networks and hotspot profiles. Stored keys are never shown.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkWiFi,
+		},
+	})
+
+	// --- APPLICATION / SYSTEM ---
+	registerCheck(Check{
+		ID:          "system-information",
+		Name:        "System Information",
+		Category:    "application_system",
+		Description: "Displays hostname, OS version, architecture and BIOS details, then opens the system About page.",
+		OpensWindow: true,
+		Screenshot:  true,
+		Timeout:     5 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				r.stream("system_profiler", "SPSoftwareDataType")
+				r.stream("open", "/System/Library/PreferencePanes/Dock.prefPane") // Using Dock as generic placeholder or System Settings
+			},
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-ComputerInfo | Select-Object CsName, OsName, WindowsVersion, OsArchitecture, BiosVersion | Format-List")
+				r.powerShell("Start-Process ms-settings:about")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "installed-applications",
+		Name:        "Check installed applications",
+		Category:    "application_system",
+		Description: "Lists installed software via WMI (Windows), System Profiler (macOS) or dpkg/rpm (Linux).",
+		Timeout:     10 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("system_profiler", "SPApplicationsDataType") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-WmiObject -Class Win32_Product | Select-Object Name, Version, Vendor, InstallDate | Format-Table -AutoSize")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "ps-drives",
+		Name:        "List PS Drives",
+		Category:    "application_system",
+		Description: "Shows all mounted drives and volume usage.",
+		Platforms: map[string]CheckFunc{
+			"darwin":  func(r *checkRun) { r.stream("df", "-h") },
+			"windows": func(r *checkRun) { r.powerShell("Get-PSDrive | Format-Table -AutoSize") },
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "hklm-registry",
+		Name:        "Access HKLM Registry",
+		Category:    "application_system",
+		Description: "Reads critical HKLM registry paths (Windows), global defaults (macOS) or system-wide configuration (Linux).",
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("defaults", "read", "NSGlobalDomain") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-ItemProperty HKLM:\\Software\\Microsoft\\Windows\\CurrentVersion | Select-Object -Property ProgramFilesDir, CommonFilesDir, DevicePath")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "startup-services",
+		Name:        "Startup Services",
+		Category:    "application_system",
+		Description: "Lists services configured to start automatically.",
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("ls", "-la", "/Library/LaunchDaemons") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-CimInstance Win32_Service | Where-Object StartMode -eq 'Auto' | Select-Object Name, State, StartMode, PathName | Format-Table -AutoSize")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "registry-check",
+		Name:        "Registry Check",
+		Category:    "application_system",
+		Description: "Inspects startup commands defined in the Registry, LaunchAgents or XDG autostart.",
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("ls", "-la", "/Library/LaunchAgents") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-CimInstance Win32_StartupCommand | Select-Object Name, Command, Location | Format-Table -AutoSize")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "registry-editor",
+		Name:        "Registry Editor",
+		Category:    "application_system",
+		Description: "Opens regedit (Windows), Preferences (macOS) or /etc (Linux) for manual inspection.",
+		OpensWindow: true,
+		Screenshot:  true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("open", "/Library/Preferences") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Write-Output 'Registry Editor cannot be run silently. Please use system tools if GUI access is needed.'")
+				r.powerShell("Start-Process regedit")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "task-manager",
+		Name:        "Task Manager",
+		Category:    "application_system",
+		Description: "Lists top CPU-consuming processes and opens Task Manager / Activity Monitor.",
+		OpensWindow: true,
+		Screenshot:  true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("open", "-a", "Activity Monitor") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-Process | Sort-Object CPU -Descending | Select-Object -First 20 | Format-Table -AutoSize")
+				r.powerShell("Start-Process taskmgr")
+			},
+		},
+	})
+
+	// --- MALWARE / ANTI VIRUS ---
+	registerCheck(Check{
+		ID:          "security-status",
+		Name:        "Security Status",
+		Category:    "malware_antivirus",
+		Description: "Checks Windows Defender, Gatekeeper/SIP or AppArmor/SELinux.",
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				r.header("OS      : macOS", "Status  : Checking Security Status...")
+
+				// 3. Gatekeeper status
+				r.log("[ Gatekeeper Status ]")
+				r.stream("spctl", "--status")
+
+				// 4. SIP (System Integrity Protection)
+				r.log("\n[ System Integrity Protection (SIP) ]")
+				r.stream("csrutil", "status")
+			},
+			"windows": func(r *checkRun) {
+				r.header("OS      : Windows", "Engine  : Microsoft Defender", "Status  : Checking Security Status...")
+
+				// AntivirusEnabled, AMServiceEnabled
+				r.powerShell("Get-MpComputerStatus | Select-Object -Property AntivirusEnabled,AMServiceEnabled,AntispywareEnabled | Format-List")
+
+				// Detect third-party
+				r.powerShell("Get-CimInstance -Namespace root/SecurityCenter2 -ClassName AntivirusProduct | Select-Object -Property displayName,productState | Format-List")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "firewall-status",
+		Name:        "Firewall Status",
+		Category:    "malware_antivirus",
+		Description: "Reports whether the host firewall is enabled per profile, summarises inbound allow rules and flags remote-access ports open to any source.",
+		Platforms: map[string]CheckFunc{
+			platformAny: checkFirewall,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "protection-health",
+		Name:        "Protection Health",
+		Category:    "malware_antivirus",
+		Description: "Verifies signature age and real-time protection status.",
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				r.header("OS      : macOS", "Status  : Checking Protection Health...")
+
+				// 1. XProtect status
+				r.log("[ XProtect Status ]")
+				r.stream("bash", "-c", "defaults read /System/Library/CoreServices/XProtect.bundle/Contents/Info.plist CFBundleShortVersionString")
+
+				// 2. MRT (Malware Removal Tool)
+				r.log("\n[ MRT Status ]")
+				r.stream("bash", "-c", "if [ -d \"/System/Library/CoreServices/MRT.app\" ]; then defaults read /System/Library/CoreServices/MRT.app/Contents/Info.plist CFBundleShortVersionString; else echo \"MRT not found\"; fi")
+			},
+			"windows": func(r *checkRun) {
+				r.header("OS      : Windows", "Engine  : Microsoft Defender", "Status  : Checking Protection Health...")
+
+				// RealTimeProtection, Updates, etc.
+				r.powerShell("Get-MpComputerStatus | Select-Object -Property RealTimeProtectionEnabled,BehaviorMonitorEnabled,IoavProtectionEnabled,NISEnabled,AntivirusSignatureLastUpdated,QuickScanAge,FullScanAge | Format-List")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "quick-scan",
+		Name:        "Run Quick Scan",
+		Category:    "malware_antivirus",
+		Description: "Starts a Windows Defender Quick Scan (Windows) or inspects persistence folders (macOS, Linux).",
+		Exclusive:   true,
+		Timeout:     30 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				r.header("OS      : macOS", "Status  : Persistence Inspection (Passive)")
+
+				// 5. Optional passive inspection
+				r.log("[INFO] Scanning LaunchAgents and LaunchDaemons for persistence indicators...")
+				r.log("[ LaunchAgents ]")
+				r.stream("ls", "-la", "/Library/LaunchAgents")
+				r.stream("ls", "-la", os.Getenv("HOME")+"/Library/LaunchAgents")
+
+				r.log("\n[ LaunchDaemons ]")
+				r.stream("ls", "-la", "/Library/LaunchDaemons")
+			},
+			"windows": func(r *checkRun) {
+				r.header("OS      : Windows", "Engine  : Microsoft Defender", "Status  : Initiating Quick Scan...")
+
+				r.log("[WARN] This will start a Windows Defender Quick Scan.")
+				// Trigger scan and stream output (Note: Start-MpScan might output to host if not job)
+				r.powerShell("Start-MpScan -ScanType QuickScan | Out-String")
+			},
+		},
+	})
+
+	// --- REMOTE SERVICES ---
+	registerCheck(Check{
+		ID:          "service-ports",
+		Name:        "Check active network service ports",
+		Category:    "remote_services",
+		Description: "Scans the configured target (default localhost) for open TCP/UDP service ports and grabs their banners.",
+		Timeout:     5 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			platformAny: checkPortScan,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "tls-certificates",
+		Name:        "Inspect TLS certificates",
+		Category:    "remote_services",
+		Description: "Inspects the certificates of local TLS services and flags expired, expiring, self-signed and weak-key ones.",
+		Timeout:     5 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			platformAny: checkTLS,
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "browser-extensions",
+		Name:        "Check installed browser extensions",
+		Category:    "remote_services",
+		Description: "Enumerates installed Chrome, Edge, Firefox and Safari extensions.",
+		Timeout:     5 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				r.header("Status : Checking Extensions...")
+
+				// Chrome
+				r.log("\n[ Google Chrome ]")
+				home, _ := os.UserHomeDir()
+				chromePath := filepath.Join(home, "Library", "Application Support", "Google", "Chrome", "Default", "Extensions")
+				r.stream("ls", "-R", chromePath)
+
+				// Safari (Approximate - usually binary)
+				r.log("\n[ Safari ]")
+				r.log("[INFO] Safari Extensions are managed via App Store. Listing related .app bundles...")
+				r.stream("mdfind", "kMDItemKind == 'Application' && kMDItemContentType == 'com.apple.safari.extension'")
+
+				// Firefox
+				r.log("\n[ Mozilla Firefox ]")
+				firefoxPath := filepath.Join(home, "Library", "Application Support", "Firefox", "Profiles")
+				r.stream("find", firefoxPath, "-name", "extensions.json")
+			},
+			"windows": func(r *checkRun) {
+				r.header("Status : Checking Extensions...")
+
+				// Browsers are independent, so query them in parallel
+				chromePath := filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data", "Default", "Extensions")
+				edgePath := filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Edge", "User Data", "Default", "Extensions")
+				firefoxPath := filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox", "Profiles")
+				r.parallel(
+					powerShellStep("Google Chrome", fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Depth 2 -ErrorAction SilentlyContinue | Select-Object FullName", chromePath)),
+					powerShellStep("Microsoft Edge", fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Depth 2 -ErrorAction SilentlyContinue | Select-Object FullName", edgePath)),
+					powerShellStep("Mozilla Firefox", fmt.Sprintf("Get-ChildItem -Path '%s' -Recurse -Include 'extensions.json','addons.json' -ErrorAction SilentlyContinue | Select-Object FullName", firefoxPath)),
+				)
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "bluetooth-devices",
+		Name:        "Device Manager (Bluetooth)",
+		Category:    "remote_services",
+		Description: "Lists Bluetooth devices known to the system.",
+		Screenshot:  true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("system_profiler", "SPBluetoothDataType") },
+			"windows": func(r *checkRun) {
+				r.powerShell("Get-PnpDevice -Class Bluetooth | Select-Object Status, Class, FriendlyName, InstanceId | Format-Table -AutoSize")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "remote-access-settings",
+		Name:        "Open Remote Access Settings",
+		Category:    "remote_services",
+		Description: "Opens the Remote Desktop / Sharing settings.",
+		OpensWindow: true,
+		Screenshot:  true,
+		Platforms: map[string]CheckFunc{
+			"darwin":  func(r *checkRun) { r.stream("open", "/System/Library/PreferencePanes/SharingPref.prefPane") },
+			"windows": func(r *checkRun) { r.powerShell("Start systempropertiesremote") },
+		},
+	})
+
+	// --- CLEAN FILES ---
+	registerCheck(Check{
+		ID:          "full-cleanup",
+		Name:        "Run Full Cleanup",
+		Category:    "clean_files",
+		Description: "Empties Trash/Recycle Bin and clears temp, browser cache and recent items.",
+		Destructive: true,
+		Exclusive:   true,
+		Timeout:     15 * time.Minute,
+		Platforms: map[string]CheckFunc{
+			// cleanupFiles is implemented per OS in the platform_*.go files
+			"darwin":  func(r *checkRun) { r.performFullCleanup(cleanupFiles) },
+			"linux":   func(r *checkRun) { r.performFullCleanup(cleanupFiles) },
+			"windows": func(r *checkRun) { r.performFullCleanup(cleanupFiles) },
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "temp-folder",
+		Name:        "Open Temp Folder",
+		Category:    "clean_files",
+		Description: "Calculates the size of and opens the temporary directory.",
+		OpensWindow: true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("open", os.Getenv("TMPDIR")) },
+			"windows": func(r *checkRun) {
+				// Calculate size AND open folder
+				r.powerShell("Get-ChildItem -Path $env:TEMP -Recurse -Force -ErrorAction SilentlyContinue | Measure-Object -Property Length -Sum | Select-Object Count, @{Name='Total Size(MB)';Expression={[math]::round($_.Sum/1MB,2)}} | Format-List")
+				r.powerShell("Start-Process explorer $env:TEMP")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "trash",
+		Name:        "Open Trash / Recycle Bin",
+		Category:    "clean_files",
+		Description: "Calculates the size of and opens the Trash / Recycle Bin.",
+		OpensWindow: true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) { r.stream("open", os.Getenv("HOME")+"/.Trash") },
+			"windows": func(r *checkRun) {
+				// Calculate Recycle Bin size AND open
+				r.powerShell("Get-ChildItem 'C:\\$Recycle.Bin' -Recurse -Force -ErrorAction SilentlyContinue | Measure-Object -Property Length -Sum | Select-Object Count, @{Name='Total Size(MB)';Expression={[math]::round($_.Sum/1MB,2)}} | Format-List")
+				r.powerShell("Start-Process explorer shell:RecycleBinFolder")
+			},
+		},
+	})
+
+	registerCheck(Check{
+		ID:          "office-temp-files",
+		Name:        "Open Office Temp Files",
+		Category:    "clean_files",
+		Description: "Locates and opens the Microsoft Word (or LibreOffice on Linux) AutoRecovery folder.",
+		OpensWindow: true,
+		Platforms: map[string]CheckFunc{
+			"darwin": func(r *checkRun) {
+				path := os.Getenv("HOME") + "/Library/Containers/com.microsoft.Word/Data/Library/Preferences/AutoRecovery"
+				r.stream("open", path)
+			},
+			"windows": func(r *checkRun) {
+				// Check common autorecover path AND open
+				wordPath := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Word")
+				r.powerShell(fmt.Sprintf("Get-ChildItem -Path '%s' -Filter *.asd -Recurse -ErrorAction SilentlyContinue | Select-Object Name, Length, LastWriteTime | Format-Table", wordPath))
+				r.powerShell(fmt.Sprintf("Start-Process explorer '%s'", wordPath))
+			},
+		},
+	})
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Linux implementations of the checks registered in checks.go, and their
+// helpers. They are only compiled into Linux builds, so registerCheck adds
+// them from linuxChecks instead of checks.go naming them directly.
+
+// linuxChecks maps check IDs to their Linux implementations.
+var linuxChecks = map[string]CheckFunc{
+	"system-information":     linuxSystemInfo,
+	"installed-applications": linuxPackages,
+	"ps-drives":              linuxDrives,
+	"hklm-registry":          linuxSystemConfig,
+	"startup-services":       linuxStartupServices,
+	"registry-check":         linuxAutostart,
+	"registry-editor":        linuxOpenConfigDir,
+	"task-manager":           linuxTaskManager,
+	"security-status":        linuxSecurityStatus,
+	"protection-health":      linuxProtectionHealth,
+	"quick-scan":             linuxPersistenceScan,
+	"browser-extensions":     linuxBrowserExtensions,
+	"bluetooth-devices":      linuxBluetoothDevices,
+	"remote-access-settings": linuxRemoteAccessSettings,
+	"temp-folder":            linuxTempFolder,
+	"trash":                  linuxTrash,
+	"office-temp-files":      linuxOfficeTempFiles,
+}
+
+// xdgDir returns the XDG base directory from env, or fallback relative to
+// the home directory when it is unset.
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, fallback)
+}
+
+// linuxTrashDir is the user's trash as defined by the XDG trash spec.
+func linuxTrashDir() string {
+	return filepath.Join(xdgDir("XDG_DATA_HOME", ".local/share"), "Trash")
+}
+
+// readTrimmed returns the trimmed contents of a small file, or "" if it
+// cannot be read.
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// hasCommand reports whether a command is on the PATH.
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// openFirst runs the first available command, for opening a settings or
+// monitor window. Desktop environments ship different tools.
+func (r *checkRun) openFirst(what string, candidates ...[]string) {
+	for _, argv := range candidates {
+		if hasCommand(argv[0]) {
+			r.stream(argv[0], argv[1:]...)
+			return
+		}
+	}
+	r.log(fmt.Sprintf("[INFO] No %s found on this desktop; open it manually if needed.", what))
+}
+
+// linuxSystemInfo shows the OS and hardware identity.
+func linuxSystemInfo(r *checkRun) {
+	r.stream("hostnamectl")
+
+	// BIOS details are world-readable in sysfs, unlike dmidecode
+	r.log("\n[ BIOS ]")
+	for _, field := range []struct{ label, file string }{
+		{"Vendor ", "bios_vendor"},
+		{"Version", "bios_version"},
+		{"Date   ", "bios_date"},
+		{"Product", "product_name"},
+	} {
+		if v := readTrimmed(filepath.Join("/sys/class/dmi/id", field.file)); v != "" {
+			r.log(fmt.Sprintf("%s : %s", field.label, v))
+		}
+	}
+
+	r.openFirst("About window", []string{"gnome-control-center", "info-overview"}, []string{"kinfocenter"})
+}
+
+// linuxPackages lists installed packages from whichever package managers
+// are present.
+func linuxPackages(r *checkRun) {
+	r.header("Status : Listing packages...")
+	found := false
+	if hasCommand("dpkg-query") {
+		found = true
+		r.log("[ dpkg ]")
+		r.stream("dpkg-query", "-W", "-f=${Package}\t${Version}\n")
+	}
+	if hasCommand("rpm") {
+		found = true
+		r.log("\n[ rpm ]")
+		r.stream("rpm", "-qa", "--queryformat", "%{NAME}\t%{VERSION}-%{RELEASE}\n")
+	}
+	if hasCommand("snap") {
+		r.log("\n[ snap ]")
+		r.stream("snap", "list")
+	}
+	if hasCommand("flatpak") {
+		r.log("\n[ flatpak ]")
+		r.stream("flatpak", "list", "--app")
+	}
+	if !found {
+		r.fail("No supported package manager (dpkg or rpm) found.")
+	}
+}
+
+// linuxSecurityStatus reports the mandatory access control module. The host
+// firewall needs root to read and is covered by the Firewall Status check.
+func linuxSecurityStatus(r *checkRun) {
+	r.header("OS      : Linux", "Status  : Checking Security Status...")
+
+	r.log("[ Mandatory Access Control ]")
+	switch {
+	case readTrimmed("/sys/module/apparmor/parameters/enabled") == "Y":
+		r.log("[OK] AppArmor is enabled.")
+	case readTrimmed("/sys/fs/selinux/enforce") == "1":
+		r.log("[OK] SELinux is enforcing.")
+	case readTrimmed("/sys/fs/selinux/enforce") == "0":
+		r.log("[WARN] SELinux is in permissive mode.")
+	default:
+		r.log("[WARN] Neither AppArmor nor SELinux is active.")
+	}
+
+	r.log("\n[ Firewall ]")
+	r.log("[INFO] See Firewall Status (firewall-status) for the host firewall and its rules.")
+}
+
+// clamavDatabases are the ClamAV signature files, in the default location.
+var clamavDatabases = []string{"main", "daily", "bytecode"}
+
+// linuxProtectionHealth reports the ClamAV engine and signature age.
+func linuxProtectionHealth(r *checkRun) {
+	r.header("OS      : Linux", "Engine  : ClamAV", "Status  : Checking Protection Health...")
+	if !hasCommand("clamscan") {
+		r.log("[WARN] ClamAV is not installed; no antivirus engine found.")
+		return
+	}
+	r.stream("clamscan", "--version")
+
+	r.log("\n[ Signature Databases ]")
+	var newest time.Time
+	for _, name := range clamavDatabases {
+		for _, ext := range []string{".cvd", ".cld"} {
+			info, err := os.Stat(filepath.Join("/var/lib/clamav", name+ext))
+			if err != nil {
+				continue
+			}
+			r.log(fmt.Sprintf("%-9s: %s", name, info.ModTime().Format("2006-01-02 15:04")))
+			if info.ModTime().After(newest) {
+				newest = info.ModTime()
+			}
+		}
+	}
+	switch age := time.Since(newest); {
+	case newest.IsZero():
+		r.log("[WARN] No signature databases found in /var/lib/clamav.")
+	case age > 7*24*time.Hour:
+		r.log(fmt.Sprintf("[WARN] Signatures are %d days old; run freshclam.", int(age.Hours()/24)))
+	default:
+		r.log("[OK] Signatures updated within the last week.")
+	}
+}
+
+// linuxBrowserProfiles are the extension folders of common browsers,
+// relative to the home directory.
+var linuxBrowserProfiles = []struct{ name, path string }{
+	{"Google Chrome", ".config/google-chrome/Default/Extensions"},
+	{"Chromium", ".config/chromium/Default/Extensions"},
+	{"Microsoft Edge", ".config/microsoft-edge/Default/Extensions"},
+	{"Mozilla Firefox", ".mozilla/firefox"},
+}
+
+// linuxBrowserExtensions lists extensions of the browsers that are installed.
+func linuxBrowserExtensions(r *checkRun) {
+	r.header("Status : Checking Extensions...")
+	home, _ := os.UserHomeDir()
+
+	var steps []Step
+	for _, b := range linuxBrowserProfiles {
+		path := filepath.Join(home, b.path)
+		if _, err := os.Stat(path); err != nil {
+			r.log(fmt.Sprintf("[INFO] %s: no profile found.", b.name))
+			continue
+		}
+		if b.name == "Mozilla Firefox" {
+			steps = append(steps, Step{Label: b.name, Command: "find", Args: []string{path, "-name", "extensions.json"}})
+		} else {
+			steps = append(steps, Step{Label: b.name, Command: "find", Args: []string{path, "-mindepth", "1", "-maxdepth", "2"}})
+		}
+	}
+	r.parallel(steps...)
+}
+
+// linuxDrives shows mounted file systems and block devices.
+func linuxDrives(r *checkRun) {
+	r.stream("df", "-h", "-x", "tmpfs", "-x", "squashfs", "-x", "devtmpfs")
+	r.stream("lsblk", "-o", "NAME,SIZE,TYPE,RM,MOUNTPOINT")
+}
+
+// linuxSystemConfig shows the OS release and system-wide environment.
+func linuxSystemConfig(r *checkRun) {
+	r.stream("cat", "/etc/os-release", "/etc/environment")
+}
+
+// linuxStartupServices lists the enabled systemd services.
+func linuxStartupServices(r *checkRun) {
+	r.stream("systemctl", "list-unit-files", "--type=service", "--state=enabled", "--no-pager")
+}
+
+// linuxAutostart lists XDG autostart entries and enabled systemd user units.
+func linuxAutostart(r *checkRun) {
+	r.log("[ XDG Autostart ]")
+	r.stream("ls", "-la", "/etc/xdg/autostart")
+	r.stream("ls", "-la", filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "autostart"))
+
+	r.log("\n[ systemd User Units ]")
+	r.stream("systemctl", "--user", "list-unit-files", "--state=enabled", "--no-pager")
+}
+
+// linuxOpenConfigDir opens /etc for manual inspection.
+func linuxOpenConfigDir(r *checkRun) {
+	r.stream("xdg-open", "/etc")
+}
+
+// linuxTaskManager lists the top CPU consumers and opens a system monitor.
+func linuxTaskManager(r *checkRun) {
+	r.stream("bash", "-c", "ps -eo pid,user,pcpu,pmem,comm --sort=-pcpu | head -n 21")
+	r.openFirst("system monitor", []string{"gnome-system-monitor"}, []string{"plasma-systemmonitor"}, []string{"ksysguard"})
+}
+
+// linuxPersistenceScan inspects systemd units, cron and autostart entries
+// for persistence.
+func linuxPersistenceScan(r *checkRun) {
+	r.header("OS      : Linux", "Status  : Persistence Inspection (Passive)")
+
+	r.log("[INFO] Scanning systemd units, cron and autostart entries for persistence indicators...")
+	r.log("[ systemd (local units) ]")
+	r.stream("ls", "-la", "/etc/systemd/system")
+
+	r.log("\n[ Cron ]")
+	r.stream("ls", "-la", "/etc/cron.d")
+	r.stream("crontab", "-l")
+
+	r.log("\n[ Autostart ]")
+	r.stream("ls", "-la", filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "autostart"))
+}
+
+// linuxBluetoothDevices lists known Bluetooth devices.
+func linuxBluetoothDevices(r *checkRun) {
+	r.stream("bluetoothctl", "devices")
+}
+
+// linuxRemoteAccessSettings opens the desktop sharing settings.
+func linuxRemoteAccessSettings(r *checkRun) {
+	r.openFirst("sharing settings", []string{"gnome-control-center", "sharing"}, []string{"systemsettings", "kcm_krdpserver"})
+}
+
+// linuxTempFolder shows the size of and opens the temporary directory.
+func linuxTempFolder(r *checkRun) {
+	r.stream("du", "-sh", os.TempDir())
+	r.stream("xdg-open", os.TempDir())
+}
+
+// linuxTrash shows the size of and opens the trash.
+func linuxTrash(r *checkRun) {
+	r.stream("du", "-sh", filepath.Join(linuxTrashDir(), "files"))
+	r.stream("xdg-open", "trash:///")
+}
+
+// linuxOfficeTempFiles lists and opens the LibreOffice backup folder.
+func linuxOfficeTempFiles(r *checkRun) {
+	path := filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "libreoffice", "4", "user", "backup")
+	r.stream("ls", "-la", path)
+	r.stream("xdg-open", path)
+}
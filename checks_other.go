@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// linuxChecks is empty outside Linux builds, where the Linux
+// implementations in checks_linux.go are not compiled; the catalog then
+// lists only the platforms this build can run.
+var linuxChecks = map[string]CheckFunc{}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Probe types of the connectivity suite.
+const (
+	probeICMP = "icmp"
+	probeDNS  = "dns"
+	probeTCP  = "tcp"
+	probeHTTP = "http"
+)
+
+// defaultProbeTimeout applies to probes that do not set their own.
+const defaultProbeTimeout = 3 * time.Second
+
+// ConnectivityProbe is one entry of the connectivity suite.
+type ConnectivityProbe struct {
+	Type      string `json:"type"`               // icmp, dns, tcp or http
+	Target    string `json:"target"`             // Host (icmp), name (dns), host:port (tcp) or URL (http)
+	Resolver  string `json:"resolver,omitempty"` // dns only: resolver IP[:port]; empty for the system resolver
+	TimeoutMs int    `json:"timeoutMs,omitempty"`
+}
+
+// ConnectivitySettings configures the connectivity suite. An empty probe
+// list selects defaultConnectivityProbes.
+type ConnectivitySettings struct {
+	Probes []ConnectivityProbe `json:"probes,omitempty"`
+}
+
+// defaultConnectivityProbes cover each layer against well-known public
+// services.
+var defaultConnectivityProbes = []ConnectivityProbe{
+	{Type: probeICMP, Target: "8.8.8.8"},
+	{Type: probeDNS, Target: "example.com"},
+	{Type: probeDNS, Target: "example.com", Resolver: "1.1.1.1"},
+	{Type: probeTCP, Target: "1.1.1.1:443"},
+	{Type: probeHTTP, Target: "https://example.com/"},
+}
+
+// validate checks every probe definition.
+func (s ConnectivitySettings) validate() error {
+	for i, p := range s.Probes {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("probe %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the target fits the probe type.
+func (p ConnectivityProbe) validate() error {
+	if p.TimeoutMs != 0 && (p.TimeoutMs < 100 || p.TimeoutMs > 30000) {
+		return errors.New("timeoutMs must be between 100 and 30000")
+	}
+	if p.Resolver != "" && p.Type != probeDNS {
+		return errors.New("resolver is only valid for dns probes")
+	}
+	switch p.Type {
+	case probeICMP, probeDNS:
+		if !hostnamePattern.MatchString(p.Target) {
+			return fmt.Errorf("invalid target %q", p.Target)
+		}
+		if p.Resolver != "" && resolverAddress(p.Resolver) == "" {
+			return fmt.Errorf("invalid resolver %q", p.Resolver)
+		}
+	case probeTCP:
+		if _, port, err := net.SplitHostPort(p.Target); err != nil || port == "" {
+			return fmt.Errorf("target %q must be host:port", p.Target)
+		}
+	case probeHTTP:
+		u, err := url.Parse(p.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("target %q must be an http(s) URL", p.Target)
+		}
+	default:
+		return fmt.Errorf("unknown type %q (use icmp, dns, tcp or http)", p.Type)
+	}
+	return nil
+}
+
+// timeout returns the probe's timeout.
+func (p ConnectivityProbe) timeout() time.Duration {
+	if p.TimeoutMs > 0 {
+		return time.Duration(p.TimeoutMs) * time.Millisecond
+	}
+	return defaultProbeTimeout
+}
+
+// ProbeResult is one row of the connectivity matrix.
+type ProbeResult struct {
+	ConnectivityProbe
+	OK        bool    `json:"ok"`
+	LatencyMs float64 `json:"latencyMs"`
+	Detail    string  `json:"detail,omitempty"` // Resolved addresses, HTTP status, ...
+	Error     string  `json:"error,omitempty"`
+}
+
+// checkConnectivity runs every configured probe in parallel and reports a
+// pass/fail matrix. Each failed probe counts as a failed step.
+func checkConnectivity(r *checkRun) {
+	probes := r.app.GetSettings().Connectivity.Probes
+	if len(probes) == 0 {
+		probes = defaultConnectivityProbes
+	}
+	r.header(fmt.Sprintf("Probes : %d", len(probes)))
+
+	results := make([]ProbeResult, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, p ConnectivityProbe) {
+			defer wg.Done()
+			results[i] = runProbe(r.ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+	if r.stopped() {
+		return
+	}
+
+	r.setData("connectivity", results)
+	r.log(formatProbeMatrix(results))
+	for _, res := range results {
+		if !res.OK {
+			r.fail(fmt.Sprintf("%s %s: %s", strings.ToUpper(res.Type), res.Target, res.Error))
+		}
+	}
+}
+
+// formatProbeMatrix renders the results as an aligned text table.
+func formatProbeMatrix(results []ProbeResult) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tTYPE\tTARGET\tVIA\tLATENCY\tDETAIL")
+	for _, res := range results {
+		status, latency, detail := "PASS", fmt.Sprintf("%.1f ms", res.LatencyMs), res.Detail
+		if !res.OK {
+			status, latency, detail = "FAIL", "-", res.Error
+		}
+		via := res.Resolver
+		if via == "" {
+			via = "-"
+			if res.Type == probeDNS {
+				via = "system"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", status, strings.ToUpper(res.Type), res.Target, via, latency, detail)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// runProbe runs a single probe within its timeout.
+func runProbe(ctx context.Context, p ConnectivityProbe) ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout())
+	defer cancel()
+
+	res := ProbeResult{ConnectivityProbe: p}
+	start := time.Now()
+	var err error
+	switch p.Type {
+	case probeICMP:
+		var rtt time.Duration
+		rtt, err = pingHost(ctx, p.Target, p.timeout())
+		start = time.Now().Add(-rtt) // Report the echo round trip, not process start-up
+	case probeDNS:
+		res.Detail, err = probeResolve(ctx, p.Target, p.Resolver)
+	case probeTCP:
+		var conn net.Conn
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", p.Target)
+		if err == nil {
+			res.Detail = "connected from " + conn.LocalAddr().String()
+			conn.Close()
+		}
+	case probeHTTP:
+		res.Detail, err = probeHTTPHead(ctx, p.Target)
+	}
+
+	res.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	res.OK = err == nil
+	if err != nil {
+		res.Error = err.Error()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			res.Error = fmt.Sprintf("no answer within %s", p.timeout())
+		}
+	}
+	return res
+}
+
+// resolverAddress returns the host:port of a resolver given as IP or
+// IP:port, or "" if it is invalid.
+func resolverAddress(resolver string) string {
+	if ip := net.ParseIP(strings.Trim(resolver, "[]")); ip != nil {
+		return net.JoinHostPort(ip.String(), "53")
+	}
+	host, port, err := net.SplitHostPort(resolver)
+	if err != nil || net.ParseIP(host) == nil {
+		return ""
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return ""
+	}
+	return resolver
+}
+
+// probeResolve looks the name up via the system resolver or, if set, the
+// given one, and returns the addresses found.
+func probeResolve(ctx context.Context, name, resolver string) (string, error) {
+	res := net.DefaultResolver
+	if resolver != "" {
+		addr := resolverAddress(resolver)
+		res = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}
+	}
+	addrs, err := res.LookupHost(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(addrs, ", "), nil
+}
+
+// probeHTTPHead sends a HEAD request (honouring the proxy environment) and
+// returns the status. 4xx/5xx responses count as failures.
+func probeHTTPHead(ctx context.Context, target string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{
+		// Report redirects instead of following them to another host
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return "HTTP " + resp.Status, nil
+}
+
+// pingHost sends one ICMP echo request, natively where raw sockets are
+// permitted and otherwise with the system ping tool.
+func pingHost(ctx context.Context, host string, timeout time.Duration) (time.Duration, error) {
+	ip, err := resolveTarget(ctx, host)
+	if err != nil {
+		return 0, err
+	}
+	if ip.To4() != nil {
+		rtt, err := pingICMP(ctx, ip, timeout)
+		if !errors.Is(err, errRawSocket) {
+			return rtt, err
+		}
+	}
+	return pingTool(ctx, ip, timeout)
+}
+
+// pingICMP sends one echo request over a raw socket.
+func pingICMP(ctx context.Context, dst net.IP, timeout time.Duration) (time.Duration, error) {
+	conn, err := listenICMP(dst)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	id, seq := nextICMPID(), int(time.Now().UnixNano()&0x7fff)
+	sent := time.Now()
+	if _, err := conn.WriteTo(icmpEchoRequest(id, seq), &net.IPAddr{IP: dst}); err != nil {
+		return 0, err
+	}
+	from, msgType, ok := awaitICMPReply(conn, make([]byte, 1500), id, seq, sent.Add(timeout))
+	switch {
+	case !ok:
+		return 0, fmt.Errorf("no echo reply within %s", timeout)
+	case msgType != icmpEchoReply:
+		return 0, fmt.Errorf("%s reported the host unreachable", from)
+	}
+	return time.Since(sent), nil
+}
+
+// pingTimePattern extracts the round trip from English ping output
+// ("time=12.3 ms", "time<1ms"). Other locales fall back to wall time.
+var pingTimePattern = regexp.MustCompile(`time[=<]([\d.]+) ?ms`)
+
+// pingTool runs the system ping once. Its exit code decides the result.
+func pingTool(ctx context.Context, dst net.IP, timeout time.Duration) (time.Duration, error) {
+	var args []string
+	switch runtime.GOOS {
+	case "windows":
+		args = []string{"-n", "1", "-w", strconv.Itoa(int(timeout.Milliseconds()))}
+	case "darwin":
+		args = []string{"-c", "1", "-t", strconv.Itoa(max(1, int(timeout.Seconds())))}
+	default:
+		args = []string{"-c", "1", "-W", strconv.Itoa(max(1, int(timeout.Seconds())))}
+	}
+	cmd := exec.CommandContext(ctx, "ping", append(args, dst.String())...)
+	cmd.SysProcAttr = getSysProcAttr()
+	start := time.Now()
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("no echo reply (ping: %v)", err)
+	}
+	if m := pingTimePattern.FindSubmatch(out); m != nil {
+		if ms, err := strconv.ParseFloat(string(m[1]), 64); err == nil {
+			return time.Duration(ms * float64(time.Millisecond)), nil
+		}
+	}
+	return time.Since(start), nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunProbeTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	res := runProbe(context.Background(), ConnectivityProbe{Type: probeTCP, Target: ln.Addr().String()})
+	if !res.OK || !strings.HasPrefix(res.Detail, "connected from 127.0.0.1:") {
+		t.Errorf("open port: got %+v, want a pass", res)
+	}
+
+	// A port that was just released refuses the connection
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := closed.Addr().String()
+	closed.Close()
+	res = runProbe(context.Background(), ConnectivityProbe{Type: probeTCP, Target: addr})
+	if res.OK || !strings.Contains(res.Error, "refused") {
+		t.Errorf("closed port: got %+v, want connection refused", res)
+	}
+}
+
+func TestRunProbeHTTP(t *testing.T) {
+	tests := []struct {
+		status int
+		ok     bool
+		want   string
+	}{
+		{http.StatusNoContent, true, "HTTP 204 No Content"},
+		{http.StatusFound, true, "HTTP 302 Found"}, // Redirects are reported, not followed
+		{http.StatusServiceUnavailable, false, "HTTP 503 Service Unavailable"},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodHead {
+				t.Errorf("method = %s, want HEAD", req.Method)
+			}
+			if tt.status == http.StatusFound {
+				w.Header().Set("Location", "http://example.invalid/")
+			}
+			w.WriteHeader(tt.status)
+		}))
+		res := runProbe(context.Background(), ConnectivityProbe{Type: probeHTTP, Target: srv.URL})
+		srv.Close()
+
+		got := res.Detail
+		if !res.OK {
+			got = res.Error
+		}
+		if res.OK != tt.ok || got != tt.want {
+			t.Errorf("status %d: got ok=%v %q, want ok=%v %q", tt.status, res.OK, got, tt.ok, tt.want)
+		}
+	}
+}
+
+// serveDNS answers A queries on a loopback UDP socket with answer, and
+// AAAA queries with no records. With a nil answer it never replies.
+func serveDNS(t *testing.T, answer net.IP) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, from, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if answer == nil || n < 12 {
+				continue
+			}
+			// The question: labels up to the root label, then type and class
+			end := 12
+			for end < n && buf[end] != 0 {
+				end += int(buf[end]) + 1
+			}
+			end += 5
+			if end > n {
+				continue
+			}
+			qtype := binary.BigEndian.Uint16(buf[end-4:])
+
+			resp := append([]byte{}, buf[:end]...)
+			binary.BigEndian.PutUint16(resp[2:], 0x8180) // Response, recursion available
+			binary.BigEndian.PutUint16(resp[8:], 0)      // No authority records
+			binary.BigEndian.PutUint16(resp[10:], 0)     // No additional records
+			binary.BigEndian.PutUint16(resp[6:], 0)      // No answers unless type A
+			if qtype == 1 {
+				binary.BigEndian.PutUint16(resp[6:], 1)
+				resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+				resp = append(resp, answer.To4()...)
+			}
+			pc.WriteTo(resp, from)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestRunProbeDNS(t *testing.T) {
+	resolver := serveDNS(t, net.IPv4(192, 0, 2, 10))
+	res := runProbe(context.Background(), ConnectivityProbe{Type: probeDNS, Target: "exam.example", Resolver: resolver})
+	if !res.OK || res.Detail != "192.0.2.10" {
+		t.Errorf("got %+v, want a pass resolving to 192.0.2.10", res)
+	}
+}
+
+func TestRunProbeTimeout(t *testing.T) {
+	resolver := serveDNS(t, nil)
+	res := runProbe(context.Background(), ConnectivityProbe{Type: probeDNS, Target: "exam.example", Resolver: resolver, TimeoutMs: 200})
+	if res.OK || res.Error != "no answer within 200ms" {
+		t.Errorf("got %+v, want a timeout", res)
+	}
+}
+
+func TestConnectivityProbeValidate(t *testing.T) {
+	tests := []struct {
+		probe ConnectivityProbe
+		err   string // Substring of the error; empty if valid
+	}{
+		{ConnectivityProbe{Type: probeICMP, Target: "8.8.8.8"}, ""},
+		{ConnectivityProbe{Type: probeDNS, Target: "example.com", Resolver: "1.1.1.1"}, ""},
+		{ConnectivityProbe{Type: probeDNS, Target: "example.com", Resolver: "[2606:4700:4700::1111]:53"}, ""},
+		{ConnectivityProbe{Type: probeTCP, Target: "exam-server.local:443", TimeoutMs: 1000}, ""},
+		{ConnectivityProbe{Type: probeHTTP, Target: "https://example.com/health"}, ""},
+		{ConnectivityProbe{Type: "udp", Target: "1.1.1.1:53"}, "unknown type"},
+		{ConnectivityProbe{Type: probeICMP, Target: "-c 100 8.8.8.8"}, "invalid target"},
+		{ConnectivityProbe{Type: probeDNS, Target: "example.com", Resolver: "dns.google"}, "invalid resolver"},
+		{ConnectivityProbe{Type: probeDNS, Target: "example.com", Resolver: "1.1.1.1:99999"}, "invalid resolver"},
+		{ConnectivityProbe{Type: probeTCP, Target: "1.1.1.1", Resolver: "8.8.8.8"}, "only valid for dns"},
+		{ConnectivityProbe{Type: probeTCP, Target: "1.1.1.1"}, "must be host:port"},
+		{ConnectivityProbe{Type: probeHTTP, Target: "ftp://example.com/"}, "http(s) URL"},
+		{ConnectivityProbe{Type: probeHTTP, Target: "https:///path"}, "http(s) URL"},
+		{ConnectivityProbe{Type: probeICMP, Target: "8.8.8.8", TimeoutMs: 50}, "timeoutMs"},
+		{ConnectivityProbe{Type: probeICMP, Target: "8.8.8.8", TimeoutMs: 60000}, "timeoutMs"},
+	}
+	for _, tt := range tests {
+		err := tt.probe.validate()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%+v: unexpected error %v", tt.probe, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%+v: got error %v, want %q", tt.probe, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// customChecksFileName is stored next to the executable (see getAppBaseDir).
+const customChecksFileName = "checkpoint-checks.json"
+
+// customCategory is the default category for checks defined in the checks file.
+const customCategory = "custom"
+
+// customCheckIDPattern restricts IDs to the form of the built-in ones, so
+// they are safe in element IDs and selectors of the frontend.
+var customCheckIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CustomCheckDef is a check definition from checkpoint-checks.json.
+type CustomCheckDef struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	Category    string                      `json:"category,omitempty"` // Defaults to "custom"
+	Description string                      `json:"description,omitempty"`
+	Timeout     int                         `json:"timeoutSeconds,omitempty"`
+	Screenshot  bool                        `json:"screenshot,omitempty"` // Also capture a screenshot on success
+	OpensWindow bool                        `json:"opensWindow,omitempty"`
+	Platforms   map[string]CustomCommandDef `json:"platforms"` // Keyed by GOOS or "*"
+	Expect      []CustomExpectation         `json:"expect,omitempty"`
+}
+
+// CustomCommandDef is what a custom check runs on one platform: either an
+// argv array or a PowerShell script. Neither is passed through a shell.
+type CustomCommandDef struct {
+	Command    []string `json:"command,omitempty"`
+	PowerShell string   `json:"powershell,omitempty"`
+}
+
+// CustomExpectation is a rule checked against the command's stdout.
+type CustomExpectation struct {
+	Pattern     string `json:"pattern"`               // Regular expression matched per line
+	Absent      bool   `json:"absent,omitempty"`      // Fail if the pattern matches instead
+	Level       string `json:"level,omitempty"`       // "error" (default) or "warn"
+	Description string `json:"description,omitempty"` // Shown in the log instead of the pattern
+}
+
+// customChecksFile is the layout of checkpoint-checks.json.
+type customChecksFile struct {
+	Checks []CustomCheckDef `json:"checks"`
+}
+
+// expectation is a compiled CustomExpectation.
+type expectation struct {
+	re          *regexp.Regexp
+	absent      bool
+	warn        bool
+	description string
+}
+
+// loadCustomChecks registers the checks defined in the checks file.
+// Invalid definitions are skipped and reported in the returned warnings.
+func (a *App) loadCustomChecks() (warnings []string) {
+	baseDir, err := a.getAppBaseDir()
+	if err != nil {
+		return []string{fmt.Sprintf("[WARN] Custom checks not loaded: %s", err)}
+	}
+	data, err := os.ReadFile(filepath.Join(baseDir, customChecksFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("[WARN] Custom checks not loaded: %s", err)}
+	}
+
+	var file customChecksFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return []string{fmt.Sprintf("[WARN] Invalid %s: %s", customChecksFileName, err)}
+	}
+
+	loaded := 0
+	for i, def := range file.Checks {
+		check, err := def.compile()
+		if err != nil {
+			name := def.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			warnings = append(warnings, fmt.Sprintf("[WARN] %s: skipping check %q: %s", customChecksFileName, name, err))
+			continue
+		}
+		registerCheck(check)
+		loaded++
+	}
+	if loaded > 0 {
+		warnings = append(warnings, fmt.Sprintf("[INFO] Loaded %d custom check(s) from %s.", loaded, customChecksFileName))
+	}
+	return warnings
+}
+
+// compile validates the definition and turns it into a registry entry.
+func (def CustomCheckDef) compile() (Check, error) {
+	if def.ID == "" || def.Name == "" {
+		return Check{}, errors.New("id and name are required")
+	}
+	if !customCheckIDPattern.MatchString(def.ID) {
+		return Check{}, errors.New("id must use lowercase letters, digits and hyphens (e.g. hosts-entries)")
+	}
+	if lookupCheck(def.ID) != nil || lookupCheck(def.Name) != nil {
+		return Check{}, errors.New("a check with this id or name already exists")
+	}
+
+	category := def.Category
+	if category == "" {
+		category = customCategory
+	}
+	if !knownCategory(category) {
+		return Check{}, fmt.Errorf("unknown category: %s", category)
+	}
+	if def.Timeout < 0 {
+		return Check{}, errors.New("timeoutSeconds must not be negative")
+	}
+	if len(def.Platforms) == 0 {
+		return Check{}, errors.New("no platforms defined")
+	}
+
+	var expects []expectation
+	for _, e := range def.Expect {
+		re, err := regexp.Compile(e.Pattern)
+		if err != nil || e.Pattern == "" {
+			return Check{}, fmt.Errorf("invalid expect pattern %q", e.Pattern)
+		}
+		if e.Level != "" && e.Level != levelError && e.Level != levelWarn {
+			return Check{}, fmt.Errorf("invalid expect level %q (use error or warn)", e.Level)
+		}
+		description := e.Description
+		if description == "" {
+			description = fmt.Sprintf("output matches %q", e.Pattern)
+			if e.Absent {
+				description = fmt.Sprintf("output does not match %q", e.Pattern)
+			}
+		}
+		expects = append(expects, expectation{re: re, absent: e.Absent, warn: e.Level == levelWarn, description: description})
+	}
+
+	platforms := make(map[string]CheckFunc)
+	for goos, cmd := range def.Platforms {
+		if goos != platformAny && platformNames[goos] == "" {
+			return Check{}, fmt.Errorf("unknown platform: %s", goos)
+		}
+		var step Step
+		switch {
+		case len(cmd.Command) > 0 && cmd.PowerShell != "":
+			return Check{}, fmt.Errorf("%s: set either command or powershell, not both", goos)
+		case len(cmd.Command) > 0:
+			if cmd.Command[0] == "" {
+				return Check{}, fmt.Errorf("%s: empty command", goos)
+			}
+			step = Step{Command: cmd.Command[0], Args: cmd.Command[1:]}
+		case cmd.PowerShell != "":
+			step = powerShellStep("", cmd.PowerShell)
+		default:
+			return Check{}, fmt.Errorf("%s: command or powershell is required", goos)
+		}
+		platforms[goos] = func(r *checkRun) { r.runCustom(step, expects) }
+	}
+
+	return Check{
+		ID:          def.ID,
+		Name:        def.Name,
+		Category:    category,
+		Description: def.Description,
+		OpensWindow: def.OpensWindow,
+		Screenshot:  def.Screenshot,
+		Custom:      true,
+		Timeout:     time.Duration(def.Timeout) * time.Second,
+		Platforms:   platforms,
+	}, nil
+}
+
+// knownCategory reports whether id is one of checkCategories.
+func knownCategory(id string) bool {
+	for _, cat := range checkCategories {
+		if cat.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// runCustom runs a custom check's command and evaluates its expectations
+// against the collected stdout.
+func (r *checkRun) runCustom(step Step, expects []expectation) {
+	r.header("Source : " + customChecksFileName)
+	lines := r.capture(step)
+	if r.stopped() {
+		return
+	}
+
+	r.mu.Lock()
+	failed := r.failedSteps
+	r.mu.Unlock()
+	if failed > 0 {
+		return // The command itself failed; expectations are meaningless
+	}
+
+	for _, msg := range evaluateExpectations(expects, lines) {
+		if strings.HasPrefix(msg, "[ERROR] ") {
+			r.fail(strings.TrimPrefix(msg, "[ERROR] "))
+		} else {
+			r.log(msg)
+		}
+	}
+}
+
+// evaluateExpectations checks each rule against the output lines and returns
+// one tagged log line per rule.
+func evaluateExpectations(expects []expectation, lines []string) []string {
+	var msgs []string
+	for _, e := range expects {
+		matched := false
+		for _, line := range lines {
+			if e.re.MatchString(line) {
+				matched = true
+				break
+			}
+		}
+		switch {
+		case matched != e.absent:
+			msgs = append(msgs, "[OK] Expectation met: "+e.description)
+		case e.warn:
+			msgs = append(msgs, "[WARN] Expectation not met: "+e.description)
+		default:
+			msgs = append(msgs, "[ERROR] Expectation not met: "+e.description)
+		}
+	}
+	return msgs
+}
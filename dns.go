@@ -0,0 +1,527 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// DNSSettings configures the "DNS Configuration" check.
+type DNSSettings struct {
+	AllowedResolvers []string `json:"allowedResolvers,omitempty"` // IPs or CIDRs; empty to skip the allowlist
+}
+
+// validate checks that every allowlist entry is an IP or a CIDR.
+func (s DNSSettings) validate() error {
+	for _, entry := range s.AllowedResolvers {
+		if _, err := parseAllowEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allowed returns the allowlist as networks. Invalid entries are skipped;
+// validate reports them.
+func (s DNSSettings) allowed() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, entry := range s.AllowedResolvers {
+		if n, err := parseAllowEntry(entry); err == nil {
+			nets = append(nets, n)
+		}
+	}
+	return nets
+}
+
+// parseAllowEntry parses an IP (as a single-address network) or a CIDR.
+func parseAllowEntry(entry string) (*net.IPNet, error) {
+	if strings.Contains(entry, "/") {
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("allowed resolver %q is not a valid CIDR", entry)
+		}
+		return n, nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("allowed resolver %q is not an IP or CIDR", entry)
+	}
+	bits := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// Resolver is one DNS server the system is configured to use.
+type Resolver struct {
+	Source    string `json:"source"` // Where it was found, e.g. /etc/resolv.conf
+	Interface string `json:"interface,omitempty"`
+	Server    string `json:"server"`
+	Domain    string `json:"domain,omitempty"` // Set for resolvers scoped to a domain
+}
+
+// EncryptedDNS is a DNS-over-HTTPS or DNS-over-TLS setting.
+type EncryptedDNS struct {
+	Source   string `json:"source"` // Browser or service
+	Mode     string `json:"mode"`
+	Template string `json:"template,omitempty"`
+	Bypass   bool   `json:"bypass"` // Queries skip the configured resolvers
+}
+
+// DNSAudit is the structured output of the "DNS Configuration" check.
+type DNSAudit struct {
+	Resolvers     []Resolver        `json:"resolvers"`
+	HostsFile     string            `json:"hostsFile"`
+	HostOverrides []HostsEntry      `json:"hostOverrides"` // Entries for sensitive domains (see sensitiveDomain)
+	EncryptedDNS  []EncryptedDNS    `json:"encryptedDns"`
+	ProxyEnv      map[string]string `json:"proxyEnv,omitempty"`
+	Findings      []Finding         `json:"findings"`
+}
+
+// stubResolvers are the local forwarders distributions put in resolv.conf
+// (systemd-resolved and the dnsmasq started by NetworkManager).
+var stubResolvers = map[string]bool{
+	"127.0.0.53": true,
+	"127.0.0.54": true,
+	"127.0.1.1":  true,
+}
+
+// publicResolvers are well-known public DNS services. They bypass the
+// network's resolver and its filtering.
+var publicResolvers = map[string]string{
+	"8.8.8.8":              "Google Public DNS",
+	"8.8.4.4":              "Google Public DNS",
+	"2001:4860:4860::8888": "Google Public DNS",
+	"2001:4860:4860::8844": "Google Public DNS",
+	"1.1.1.1":              "Cloudflare",
+	"1.0.0.1":              "Cloudflare",
+	"1.1.1.2":              "Cloudflare",
+	"1.1.1.3":              "Cloudflare",
+	"2606:4700:4700::1111": "Cloudflare",
+	"2606:4700:4700::1001": "Cloudflare",
+	"9.9.9.9":              "Quad9",
+	"149.112.112.112":      "Quad9",
+	"2620:fe::fe":          "Quad9",
+	"208.67.222.222":       "OpenDNS",
+	"208.67.220.220":       "OpenDNS",
+	"94.140.14.14":         "AdGuard DNS",
+	"94.140.15.15":         "AdGuard DNS",
+	"76.76.2.0":            "Control D",
+	"185.228.168.9":        "CleanBrowsing",
+	"64.6.64.6":            "Verisign",
+}
+
+// browserStateFile is a browser file holding DNS-over-HTTPS settings.
+type browserStateFile struct {
+	browser string
+	firefox bool   // prefs.js rather than a Chromium "Local State" file
+	pattern string // Glob, as Firefox has one prefs.js per profile
+}
+
+// checkDNS implements "DNS Configuration": it reads the resolvers (see
+// readResolvers), the hosts file and encrypted DNS settings, and flags
+// anything that sends name resolution somewhere unexpected.
+func checkDNS(r *checkRun) {
+	r.header("Status : Reading resolver configuration...")
+	cfg := r.app.GetSettings().DNS
+	audit := DNSAudit{HostsFile: hostsFilePath()}
+
+	resolvers, err := readResolvers(r)
+	if r.stopped() {
+		return
+	}
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read resolver configuration: %v", err))
+		return
+	}
+	audit.Resolvers = resolvers
+	r.log(formatResolvers(resolvers))
+	audit.Findings = append(audit.Findings, analyzeResolvers(resolvers, cfg.allowed())...)
+
+	r.log(fmt.Sprintf("\n[ Hosts file: %s ]", audit.HostsFile))
+	if data, err := os.ReadFile(audit.HostsFile); err != nil {
+		r.log(fmt.Sprintf("[WARN] Cannot read hosts file: %v", err))
+	} else {
+		entries := parseHostsFile(string(data))
+		r.log(fmt.Sprintf("%d host name mappings.", len(entries)))
+		var findings []Finding
+		audit.HostOverrides, findings = analyzeSensitiveHosts(entries)
+		audit.Findings = append(audit.Findings, findings...)
+	}
+
+	r.log("\n[ Encrypted DNS ]")
+	for _, f := range browserStateFiles() {
+		audit.EncryptedDNS = append(audit.EncryptedDNS, readBrowserDNS(f)...)
+	}
+	audit.EncryptedDNS = append(audit.EncryptedDNS, readEncryptedDNS()...)
+	if len(audit.EncryptedDNS) == 0 {
+		r.log("No DNS-over-HTTPS or DNS-over-TLS settings found.")
+	}
+	for _, e := range audit.EncryptedDNS {
+		line := fmt.Sprintf("%s: %s", e.Source, e.Mode)
+		if e.Template != "" {
+			line += " (" + e.Template + ")"
+		}
+		r.log(line)
+		if e.Bypass {
+			audit.Findings = append(audit.Findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("%s uses encrypted DNS (%s), which bypasses the configured resolvers.", e.Source, e.Mode)})
+		}
+	}
+
+	for _, s := range envProxySettings(os.Getenv) {
+		if audit.ProxyEnv == nil {
+			audit.ProxyEnv = map[string]string{}
+			r.log("\n[ Proxy environment ]")
+		}
+		v := redactURL(s.Value)
+		audit.ProxyEnv[s.Source] = v
+		r.log(fmt.Sprintf("[INFO] %s=%s", s.Source, v))
+	}
+
+	r.setData("dns", audit)
+	if len(audit.Findings) == 0 {
+		r.log("[OK] Name resolution uses the expected resolvers.")
+	}
+	r.report(audit.Findings)
+}
+
+// formatResolvers renders the resolvers as a table.
+func formatResolvers(resolvers []Resolver) string {
+	if len(resolvers) == 0 {
+		return "No resolvers configured."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tINTERFACE\tDOMAIN\tSOURCE")
+	for _, res := range resolvers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", res.Server, dash(res.Interface), dash(res.Domain), res.Source)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// dash returns s, or "-" for an empty table cell.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// analyzeResolvers flags missing, public, unexpected and non-allowlisted
+// resolvers. Each server is reported once, however many sources list it.
+func analyzeResolvers(resolvers []Resolver, allowed []*net.IPNet) []Finding {
+	global := 0
+	for _, res := range resolvers {
+		if res.Domain == "" {
+			global++
+		}
+	}
+	if global == 0 {
+		return []Finding{{Level: levelError, Message: "No DNS resolvers are configured."}}
+	}
+
+	var findings []Finding
+	seen := map[string]bool{}
+	for _, res := range resolvers {
+		if seen[res.Server] {
+			continue
+		}
+		seen[res.Server] = true
+		if stubResolvers[res.Server] {
+			continue
+		}
+		ip := net.ParseIP(stripZone(res.Server))
+		if ip == nil {
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Resolver %q (%s) is not an IP address.", res.Server, res.Source)})
+			continue
+		}
+
+		if len(allowed) > 0 && !inNetworks(ip, allowed) {
+			findings = append(findings, Finding{Level: levelError,
+				Message: fmt.Sprintf("Resolver %s (%s) is not in the allowed resolver list.", res.Server, res.Source)})
+			continue
+		}
+		switch name, public := publicResolvers[ip.String()]; {
+		case public:
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Resolver %s is %s, a public DNS service that bypasses the network's resolver.", res.Server, name)})
+		case ip.IsLoopback():
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Resolver %s is a local DNS server other than the system stub; check which process answers on port 53.", res.Server)})
+		case !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && len(allowed) == 0:
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Resolver %s (%s) is a non-local address; expected the network's own resolver.", res.Server, res.Source)})
+		}
+	}
+	return findings
+}
+
+// inNetworks reports whether ip lies in one of nets.
+func inNetworks(ip net.IP, nets []*net.IPNet) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolverAddr reduces a server as written in resolver configuration to its
+// address: "1.1.1.1#cloudflare-dns.com", "1.1.1.1:53" and
+// "[2606:4700::1111]:53%eth0" all name a single IP.
+func resolverAddr(s string) string {
+	s, _, _ = strings.Cut(s, "#")
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	if ip, _, ok := strings.Cut(s, "%"); ok && net.ParseIP(ip) != nil {
+		s = ip
+	}
+	return s
+}
+
+// readBrowserDNS reads the DNS-over-HTTPS settings of one browser. Missing
+// files yield nothing.
+func readBrowserDNS(f browserStateFile) []EncryptedDNS {
+	paths, _ := filepath.Glob(f.pattern)
+	sort.Strings(paths)
+	var settings []EncryptedDNS
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var e EncryptedDNS
+		var ok bool
+		if f.firefox {
+			e, ok = parseFirefoxDNS(string(data))
+		} else {
+			e, ok = parseChromiumDNS(data)
+		}
+		if !ok {
+			continue
+		}
+		e.Source = f.browser
+		if len(paths) > 1 {
+			e.Source += " (" + filepath.Base(filepath.Dir(path)) + ")"
+		}
+		settings = append(settings, e)
+	}
+	return settings
+}
+
+// parseChromiumDNS reads dns_over_https from a Chromium "Local State" file.
+// "secure" mode or a custom template sends every query to the DoH server;
+// "automatic" only upgrades the configured resolver when it supports DoH.
+func parseChromiumDNS(data []byte) (EncryptedDNS, bool) {
+	var state struct {
+		DoH *struct {
+			Mode      string `json:"mode"`
+			Templates string `json:"templates"`
+		} `json:"dns_over_https"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil || state.DoH == nil {
+		return EncryptedDNS{}, false
+	}
+	return EncryptedDNS{
+		Mode:     "DNS-over-HTTPS " + dash(state.DoH.Mode),
+		Template: state.DoH.Templates,
+		Bypass:   state.DoH.Mode == "secure" || state.DoH.Templates != "",
+	}, true
+}
+
+// firefoxPrefPattern matches user_pref("name", value); lines of prefs.js.
+var firefoxPrefPattern = regexp.MustCompile(`^user_pref\("([^"]+)",\s*"?([^"]*?)"?\);`)
+
+// parseFirefoxDNS reads network.trr.* from a Firefox prefs.js file. Modes
+// 2 (DoH first) and 3 (DoH only) bypass the system resolver; 5 is off.
+func parseFirefoxDNS(data string) (EncryptedDNS, bool) {
+	var mode, uri string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		m := firefoxPrefPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "network.trr.mode":
+			mode = m[2]
+		case "network.trr.uri", "network.trr.custom_uri":
+			if uri == "" || m[1] == "network.trr.custom_uri" {
+				uri = m[2]
+			}
+		}
+	}
+	if mode == "" {
+		return EncryptedDNS{}, false
+	}
+	return EncryptedDNS{
+		Mode:     "DNS-over-HTTPS trr.mode=" + mode,
+		Template: uri,
+		Bypass:   mode == "2" || mode == "3",
+	}, true
+}
+
+// resolvedConf is the [Resolve] section of systemd-resolved's configuration.
+type resolvedConf struct {
+	DNS         []string
+	FallbackDNS []string
+	DNSOverTLS  string
+}
+
+// parseResolvedConf applies one resolved.conf file (or drop-in) to conf.
+// As in systemd, an empty assignment resets a list and later files
+// override earlier ones.
+func parseResolvedConf(data string, conf *resolvedConf) {
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "Resolve" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "DNS":
+			conf.DNS = appendServers(conf.DNS, value)
+		case "FallbackDNS":
+			conf.FallbackDNS = appendServers(conf.FallbackDNS, value)
+		case "DNSOverTLS":
+			conf.DNSOverTLS = value
+		}
+	}
+}
+
+// appendServers adds the space separated servers of value to list, or
+// resets list when value is empty.
+func appendServers(list []string, value string) []string {
+	if value == "" {
+		return nil
+	}
+	for _, s := range strings.Fields(value) {
+		list = append(list, resolverAddr(s))
+	}
+	return list
+}
+
+// resolvectlLinkPattern matches "Link 2 (eth0):" in `resolvectl dns` output.
+var resolvectlLinkPattern = regexp.MustCompile(`^Link \d+ \(([^)]+)\)$`)
+
+// parseResolvectlDNS parses `resolvectl dns`, which lists the global
+// servers and those of each link:
+//
+//	Global: 1.1.1.1#cloudflare-dns.com
+//	Link 2 (eth0): 192.168.1.1 fe80::1%eth0
+//	Link 3 (wlan0):
+func parseResolvectlDNS(data string) []Resolver {
+	var resolvers []Resolver
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		label, servers, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		iface := ""
+		if m := resolvectlLinkPattern.FindStringSubmatch(strings.TrimSpace(label)); m != nil {
+			iface = m[1]
+		} else if strings.TrimSpace(label) != "Global" {
+			continue
+		}
+		for _, s := range strings.Fields(servers) {
+			resolvers = append(resolvers, Resolver{Source: "systemd-resolved", Interface: iface, Server: resolverAddr(s)})
+		}
+	}
+	return resolvers
+}
+
+// scutilResolverPattern matches "resolver #1" in `scutil --dns` output.
+var scutilResolverPattern = regexp.MustCompile(`^resolver #\d+$`)
+
+// parseScutilDNS parses the main section of `scutil --dns`; the scoped
+// section that follows repeats the same servers per interface.
+//
+//	resolver #1
+//	  search domain[0] : lan
+//	  nameserver[0] : 192.168.1.1
+//	  if_index : 6 (en0)
+//
+//	resolver #2
+//	  domain   : local
+//	  options  : mdns
+func parseScutilDNS(data string) []Resolver {
+	var resolvers []Resolver
+	var servers []string
+	var iface, domain string
+	flush := func() {
+		for _, s := range servers {
+			resolvers = append(resolvers, Resolver{Source: "scutil --dns", Interface: iface, Server: s, Domain: domain})
+		}
+		servers, iface, domain = nil, "", ""
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "DNS configuration (") {
+			break
+		}
+		if scutilResolverPattern.MatchString(line) {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(key, "nameserver["):
+			servers = append(servers, resolverAddr(value))
+		case key == "domain":
+			domain = value
+		case key == "if_index":
+			if _, name, ok := strings.Cut(value, "("); ok {
+				iface = strings.TrimSuffix(name, ")")
+			}
+		}
+	}
+	flush()
+	return resolvers
+}
+
+// parseDNSClientServers parses "alias<TAB>server,server" lines, as printed
+// by the Get-DnsClientServerAddress script of the Windows reader.
+func parseDNSClientServers(lines []string) []Resolver {
+	var resolvers []Resolver
+	for _, line := range lines {
+		alias, servers, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		for _, s := range strings.Split(servers, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				resolvers = append(resolvers, Resolver{Source: "Get-DnsClientServerAddress", Interface: alias, Server: s})
+			}
+		}
+	}
+	return resolvers
+}
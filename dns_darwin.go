@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readResolvers parses `scutil --dns`, which reflects the resolvers in
+// use, unlike /etc/resolv.conf. A failing command is reported as a failed
+// step.
+func readResolvers(r *checkRun) ([]Resolver, error) {
+	out := r.capture(Step{Command: "scutil", Args: []string{"--dns"}, Quiet: true})
+	return parseScutilDNS(strings.Join(out, "\n")), nil
+}
+
+// readEncryptedDNS returns nothing: system-wide encrypted DNS on macOS comes
+// from configuration profiles, which show up as resolvers.
+func readEncryptedDNS() []EncryptedDNS {
+	return nil
+}
+
+// browserStateFiles lists the files holding the DNS-over-HTTPS settings of
+// common browsers.
+func browserStateFiles() []browserStateFile {
+	support := filepath.Join(os.Getenv("HOME"), "Library", "Application Support")
+	return []browserStateFile{
+		{browser: "Google Chrome", pattern: filepath.Join(support, "Google", "Chrome", "Local State")},
+		{browser: "Microsoft Edge", pattern: filepath.Join(support, "Microsoft Edge", "Local State")},
+		{browser: "Brave", pattern: filepath.Join(support, "BraveSoftware", "Brave-Browser", "Local State")},
+		{browser: "Mozilla Firefox", firefox: true, pattern: filepath.Join(support, "Firefox", "Profiles", "*", "prefs.js")},
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readResolvers reads /etc/resolv.conf. When it points at the
+// systemd-resolved stub, the upstream servers come from `resolvectl dns`
+// (per link) or the resolv.conf that systemd-resolved maintains.
+// FallbackDNS servers configured in resolved.conf are listed as well.
+func readResolvers(r *checkRun) ([]Resolver, error) {
+	data, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var resolvers []Resolver
+	stub := false
+	for _, s := range parseResolvConf(string(data)) {
+		resolvers = append(resolvers, Resolver{Source: "/etc/resolv.conf", Server: resolverAddr(s)})
+		stub = stub || s == "127.0.0.53"
+	}
+	if !stub {
+		return resolvers, nil
+	}
+
+	var upstream []Resolver
+	if hasCommand("resolvectl") {
+		out := r.capture(Step{Command: "resolvectl", Args: []string{"dns"}, Quiet: true})
+		upstream = parseResolvectlDNS(strings.Join(out, "\n"))
+	}
+	if len(upstream) == 0 {
+		for _, s := range parseResolvConf(readTrimmed("/run/systemd/resolve/resolv.conf")) {
+			upstream = append(upstream, Resolver{Source: "systemd-resolved", Server: resolverAddr(s)})
+		}
+	}
+	for _, s := range readResolvedConf().FallbackDNS {
+		upstream = append(upstream, Resolver{Source: "resolved.conf FallbackDNS", Server: s})
+	}
+	return append(resolvers, upstream...), nil
+}
+
+// readResolvedConf reads resolved.conf and its drop-ins in the order
+// systemd-resolved applies them.
+func readResolvedConf() resolvedConf {
+	var conf resolvedConf
+	files := []string{"/etc/systemd/resolved.conf"}
+	for _, dir := range []string{"/usr/lib/systemd/resolved.conf.d", "/run/systemd/resolved.conf.d", "/etc/systemd/resolved.conf.d"} {
+		dropIns, _ := filepath.Glob(filepath.Join(dir, "*.conf"))
+		files = append(files, dropIns...)
+	}
+	for _, path := range files {
+		if data, err := os.ReadFile(path); err == nil {
+			parseResolvedConf(string(data), &conf)
+		}
+	}
+	return conf
+}
+
+// readEncryptedDNS reports the DNSOverTLS setting of systemd-resolved. It
+// encrypts queries to the configured servers rather than bypassing them.
+func readEncryptedDNS() []EncryptedDNS {
+	conf := readResolvedConf()
+	if conf.DNSOverTLS == "" {
+		return nil
+	}
+	return []EncryptedDNS{{Source: "systemd-resolved", Mode: "DNS-over-TLS " + conf.DNSOverTLS}}
+}
+
+// browserStateFiles lists the files holding the DNS-over-HTTPS settings of
+// common browsers.
+func browserStateFiles() []browserStateFile {
+	home, _ := os.UserHomeDir()
+	return []browserStateFile{
+		{browser: "Google Chrome", pattern: filepath.Join(home, ".config/google-chrome/Local State")},
+		{browser: "Chromium", pattern: filepath.Join(home, ".config/chromium/Local State")},
+		{browser: "Microsoft Edge", pattern: filepath.Join(home, ".config/microsoft-edge/Local State")},
+		{browser: "Brave", pattern: filepath.Join(home, ".config/BraveSoftware/Brave-Browser/Local State")},
+		{browser: "Mozilla Firefox", firefox: true, pattern: filepath.Join(home, ".mozilla/firefox/*/prefs.js")},
+	}
+}
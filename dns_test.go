@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolverParsers(t *testing.T) {
+	resolved := func(iface, server string) Resolver {
+		return Resolver{Source: "systemd-resolved", Interface: iface, Server: server}
+	}
+	scutil := func(iface, server, domain string) Resolver {
+		return Resolver{Source: "scutil --dns", Interface: iface, Server: server, Domain: domain}
+	}
+	client := func(iface, server string) Resolver {
+		return Resolver{Source: "Get-DnsClientServerAddress", Interface: iface, Server: server}
+	}
+	tests := []struct {
+		fixture string
+		parse   func(string) []Resolver
+		want    []Resolver
+	}{
+		// Server names, ports and zones are dropped; links without servers add nothing
+		{"resolvectl_dns.txt", parseResolvectlDNS, []Resolver{
+			resolved("", "1.1.1.1"),
+			resolved("", "9.9.9.9"),
+			resolved("enp0s31f6", "192.168.1.1"),
+			resolved("enp0s31f6", "fe80::a691:b1ff:fe2c:3d4e"),
+			resolved("wg0", "10.8.0.1"),
+		}},
+		// The scoped section repeating resolver #1 is skipped
+		{"scutil_dns_darwin.txt", parseScutilDNS, []Resolver{
+			scutil("en0", "192.168.1.1", ""),
+			scutil("en0", "fe80::a691:b1ff:fe2c:3d4e", ""),
+			scutil("utun3", "10.20.0.53", "school.example"),
+		}},
+		{"dns_client_servers_windows.txt", func(data string) []Resolver { return parseDNSClientServers(strings.Split(data, "\n")) }, []Resolver{
+			client("Wi-Fi", "192.168.1.1"),
+			client("Wi-Fi", "fe80::a691:b1ff:fe2c:3d4e%12"),
+			client("Ethernet", "8.8.8.8"),
+			client("Ethernet", "8.8.4.4"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := tt.parse(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got:\n%s\nwant:\n%s", formatResolvers(got), formatResolvers(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseResolvedConf(t *testing.T) {
+	var conf resolvedConf
+	parseResolvedConf(readFixture(t, "resolved.conf"), &conf)
+	want := resolvedConf{DNS: []string{"192.168.1.1"}, FallbackDNS: []string{"1.1.1.1", "8.8.8.8"}, DNSOverTLS: "no"}
+	if !reflect.DeepEqual(conf, want) {
+		t.Errorf("resolved.conf = %+v, want %+v", conf, want)
+	}
+
+	// The drop-in resets DNS= and overrides DNSOverTLS=
+	parseResolvedConf(readFixture(t, "resolved_dropin.conf"), &conf)
+	want = resolvedConf{DNS: []string{"9.9.9.9", "2620:fe::fe"}, FallbackDNS: []string{"1.1.1.1", "8.8.8.8"}, DNSOverTLS: "opportunistic"}
+	if !reflect.DeepEqual(conf, want) {
+		t.Errorf("resolved.conf with drop-in = %+v, want %+v", conf, want)
+	}
+}
+
+func TestResolverAddr(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"1.1.1.1", "1.1.1.1"},
+		{"1.1.1.1#cloudflare-dns.com", "1.1.1.1"},
+		{"1.1.1.1:53", "1.1.1.1"},
+		{"[2606:4700::1111]:53", "2606:4700::1111"},
+		{"[2606:4700::1111]:853#cloudflare-dns.com", "2606:4700::1111"},
+		{"fe80::1%eth0", "fe80::1"},
+		{"2620:fe::fe", "2620:fe::fe"},
+		{"dns.example", "dns.example"},
+	}
+	for _, tt := range tests {
+		if got := resolverAddr(tt.in); got != tt.want {
+			t.Errorf("resolverAddr(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBrowserDNSParsers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() (EncryptedDNS, bool)
+		want EncryptedDNS
+		ok   bool
+	}{
+		{"chromium secure", func() (EncryptedDNS, bool) {
+			return parseChromiumDNS([]byte(readFixture(t, "chrome_local_state.json")))
+		},
+			EncryptedDNS{Mode: "DNS-over-HTTPS secure", Template: "https://dns.quad9.net/dns-query", Bypass: true}, true},
+		{"chromium automatic", func() (EncryptedDNS, bool) {
+			return parseChromiumDNS([]byte(`{"dns_over_https":{"mode":"automatic"}}`))
+		},
+			EncryptedDNS{Mode: "DNS-over-HTTPS automatic"}, true},
+		{"chromium unset", func() (EncryptedDNS, bool) { return parseChromiumDNS([]byte(`{"browser":{}}`)) }, EncryptedDNS{}, false},
+		// custom_uri wins over uri
+		{"firefox DoH only", func() (EncryptedDNS, bool) { return parseFirefoxDNS(readFixture(t, "firefox_prefs_doh.js")) },
+			EncryptedDNS{Mode: "DNS-over-HTTPS trr.mode=3", Template: "https://doh.example.net/dns-query", Bypass: true}, true},
+		{"firefox off", func() (EncryptedDNS, bool) { return parseFirefoxDNS(readFixture(t, "firefox_prefs.js")) },
+			EncryptedDNS{Mode: "DNS-over-HTTPS trr.mode=5"}, true},
+		{"firefox unset", func() (EncryptedDNS, bool) { return parseFirefoxDNS(`user_pref("browser.startup.page", 3);`) }, EncryptedDNS{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.got()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResolvers(t *testing.T) {
+	conf := func(server string) Resolver { return Resolver{Source: "/etc/resolv.conf", Server: server} }
+	allowlist := func(entries ...string) []*net.IPNet { return DNSSettings{AllowedResolvers: entries}.allowed() }
+	tests := []struct {
+		name      string
+		resolvers []Resolver
+		allowed   []*net.IPNet
+		want      []string
+	}{
+		{"none", nil, nil, []string{"error: No DNS resolvers are configured."}},
+		{"scoped only", parseScutilDNS(readFixture(t, "scutil_dns_darwin.txt"))[2:], nil, []string{"error: No DNS resolvers are configured."}},
+		{"stub and network resolver", []Resolver{conf("127.0.0.53"), conf("192.168.1.1"), conf("fe80::1%eth0")}, nil, nil},
+		{"public", []Resolver{conf("8.8.8.8"), {Source: "systemd-resolved", Server: "8.8.8.8"}}, nil, []string{
+			"warn: Resolver 8.8.8.8 is Google Public DNS",
+		}},
+		{"loopback", []Resolver{conf("127.0.0.1")}, nil, []string{
+			"warn: Resolver 127.0.0.1 is a local DNS server other than the system stub",
+		}},
+		{"non-local", []Resolver{conf("203.0.113.53")}, nil, []string{
+			"warn: Resolver 203.0.113.53 (/etc/resolv.conf) is a non-local address",
+		}},
+		{"not an IP", []Resolver{conf("dns.example")}, nil, []string{
+			`warn: Resolver "dns.example" (/etc/resolv.conf) is not an IP address.`,
+		}},
+		{"allowlist", []Resolver{conf("127.0.0.53"), conf("10.20.0.53"), conf("203.0.113.53"), conf("1.1.1.1")}, allowlist("10.20.0.0/16", "203.0.113.53"), []string{
+			"error: Resolver 1.1.1.1 (/etc/resolv.conf) is not in the allowed resolver list.",
+		}},
+		{"allowlisted public resolver", []Resolver{conf("9.9.9.9")}, allowlist("9.9.9.9"), []string{
+			"warn: Resolver 9.9.9.9 is Quad9",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, analyzeResolvers(tt.resolvers, tt.allowed), tt.want)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// dnsServersScript prints "alias<TAB>server,server" for every adapter that
+// has DNS servers.
+const dnsServersScript = "Get-DnsClientServerAddress | Where-Object { $_.ServerAddresses } | " +
+	"ForEach-Object { \"{0}`t{1}\" -f $_.InterfaceAlias, ($_.ServerAddresses -join ',') }"
+
+// readResolvers lists the DNS servers of each adapter. A failing command is
+// reported as a failed step.
+func readResolvers(r *checkRun) ([]Resolver, error) {
+	step := powerShellStep("", dnsServersScript)
+	step.Quiet = true
+	return parseDNSClientServers(r.capture(step)), nil
+}
+
+// readEncryptedDNS returns nothing: Windows only uses DNS-over-HTTPS for
+// servers already configured on an adapter, so it does not bypass them.
+func readEncryptedDNS() []EncryptedDNS {
+	return nil
+}
+
+// browserStateFiles lists the files holding the DNS-over-HTTPS settings of
+// common browsers.
+func browserStateFiles() []browserStateFile {
+	local, roaming := os.Getenv("LOCALAPPDATA"), os.Getenv("APPDATA")
+	return []browserStateFile{
+		{browser: "Google Chrome", pattern: filepath.Join(local, "Google", "Chrome", "User Data", "Local State")},
+		{browser: "Microsoft Edge", pattern: filepath.Join(local, "Microsoft", "Edge", "User Data", "Local State")},
+		{browser: "Brave", pattern: filepath.Join(local, "BraveSoftware", "Brave-Browser", "User Data", "Local State")},
+		{browser: "Mozilla Firefox", firefox: true, pattern: filepath.Join(roaming, "Mozilla", "Firefox", "Profiles", "*", "prefs.js")},
+	}
+}
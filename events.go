@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Streams a run event can originate from.
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+	streamSystem = "system" // Messages produced by CheckPoint itself
+)
+
+// Levels used to colour and filter run events.
+const (
+	levelHeader = "header"
+	levelInfo   = "info"
+	levelOK     = "ok"
+	levelWarn   = "warn"
+	levelError  = "error"
+)
+
+// RunEvent is the payload of the "run:event" event: one line (or block)
+// of output produced while a check runs.
+type RunEvent struct {
+	RunID     string    `json:"runId"`
+	CheckID   string    `json:"checkId"`
+	Step      int       `json:"step"`                // 1-based command index, 0 for system messages
+	StepLabel string    `json:"stepLabel,omitempty"` // Set for steps run in parallel
+	Stream    string    `json:"stream"`
+	Level     string    `json:"level"`
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+
+	// Kind and Data carry a structured record alongside the text line for
+	// checks that produce one (for example kind "hop" with a TracerouteHop).
+	Kind string `json:"kind,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// levelPrefixes maps the bracketed prefixes used in system messages to levels.
+var levelPrefixes = []struct {
+	prefix string
+	level  string
+}{
+	{"[ERROR]", levelError},
+	{"[ERR]", levelError},
+	{"[STOP]", levelError},
+	{"[TIMEOUT]", levelError},
+	{"[WARN]", levelWarn},
+	{"[CANCELLED]", levelWarn},
+	{"[OK]", levelOK},
+	{"=====", levelHeader},
+}
+
+// levelFor derives the level of a system message from its prefix.
+func levelFor(msg string) string {
+	trimmed := strings.TrimSpace(msg)
+	for _, p := range levelPrefixes {
+		if strings.HasPrefix(trimmed, p.prefix) {
+			return p.level
+		}
+	}
+	return levelInfo
+}
+
+// emit sends a structured event for the run.
+func (r *checkRun) emit(ev RunEvent) {
+	ev.RunID = r.id
+	ev.CheckID = r.check.ID
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	wailsRuntime.EventsEmit(r.app.ctx, "run:event", ev)
+}
+
+// log emits a system message for the run
+func (r *checkRun) log(msg string) {
+	r.emit(RunEvent{Stream: streamSystem, Level: levelFor(msg), Text: msg})
+}
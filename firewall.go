@@ -0,0 +1,580 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// remoteAccessPorts are the TCP ports (FTP, SSH, SMB, RDP) a firewall
+// should never open to any source on a candidate machine. Their names come
+// from the service knowledge base.
+var remoteAccessPorts = []int{21, 22, 445, 3389}
+
+// Default inbound actions of a FirewallProfile.
+const (
+	inboundAllow = "allow"
+	inboundBlock = "block"
+)
+
+// anySource is the From value of rules that accept every source address.
+const anySource = "any"
+
+// FirewallProfile is the state of one firewall profile (Windows), chain
+// (nftables, iptables) or front-end (ufw, the macOS application firewall).
+type FirewallProfile struct {
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	DefaultInbound string `json:"defaultInbound,omitempty"` // allow or block; empty if unknown
+}
+
+// FirewallRule is an inbound allow rule.
+type FirewallRule struct {
+	Name    string `json:"name"`              // Rule name, or the rule itself for line-based rule sets
+	Proto   string `json:"proto"`             // tcp, udp or any
+	Ports   string `json:"ports"`             // Local ports, e.g. "22", "80,443" or "1000-2000"; empty for every port
+	From    string `json:"from"`              // Source addresses, or "any"
+	Program string `json:"program,omitempty"` // Set when the rule only applies to one program
+}
+
+// FirewallStatus is the structured output of the "Firewall Status" check.
+type FirewallStatus struct {
+	Backend  string            `json:"backend"` // Tool the state was read with
+	Profiles []FirewallProfile `json:"profiles"`
+	Rules    []FirewallRule    `json:"rules"`
+	Findings []Finding         `json:"findings"`
+}
+
+// checkFirewall implements "Firewall Status": it reads the firewall state
+// (see readFirewall), summarises the inbound allow rules and flags rules
+// opening remote-access ports to any source.
+func checkFirewall(r *checkRun) {
+	r.header("Status : Reading firewall state...")
+	fw, err := readFirewall(r)
+	if r.stopped() {
+		return
+	}
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read firewall state: %v", err))
+		return
+	}
+	if fw.Profiles == nil {
+		fw.Profiles = []FirewallProfile{}
+	}
+	if fw.Rules == nil {
+		fw.Rules = []FirewallRule{}
+	}
+	fw.Findings = analyzeFirewall(fw, r.app.knownServices())
+
+	if fw.Backend != "" {
+		r.log("Backend : " + fw.Backend)
+	}
+	r.log(formatFirewallProfiles(fw.Profiles))
+	r.log(fmt.Sprintf("\n[ Inbound allow rules: %d ]", len(fw.Rules)))
+	if len(fw.Rules) > 0 {
+		r.log(formatFirewallRules(fw.Rules))
+	}
+
+	r.setData("firewall", fw)
+	if len(fw.Findings) == 0 {
+		r.log("[OK] The firewall is enabled and no rule opens a remote-access port to any source.")
+	}
+	r.report(fw.Findings)
+}
+
+// formatFirewallProfiles renders the profiles as a table.
+func formatFirewallProfiles(profiles []FirewallProfile) string {
+	if len(profiles) == 0 {
+		return "No firewall found."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tSTATE\tDEFAULT INBOUND")
+	for _, p := range profiles {
+		state := "OFF"
+		if p.Enabled {
+			state = "ON"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, state, dash(p.DefaultInbound))
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatFirewallRules renders the rules as a table.
+func formatFirewallRules(rules []FirewallRule) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROTO\tPORTS\tFROM\tPROGRAM\tRULE")
+	for _, rule := range rules {
+		ports := rule.Ports
+		if ports == "" {
+			ports = "all"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rule.Proto, ports, rule.From, dash(rule.Program), rule.Name)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// analyzeFirewall reports a missing or disabled firewall (error), profiles
+// allowing inbound connections by default (warning) and rules that allow
+// a remote-access port from any source (error). Rules restricted to one
+// program only count when they name the port explicitly.
+func analyzeFirewall(fw FirewallStatus, kb serviceKB) []Finding {
+	if len(fw.Profiles) == 0 {
+		return []Finding{{Level: levelError, Message: "No host firewall is active."}}
+	}
+	var findings []Finding
+	for _, p := range fw.Profiles {
+		switch {
+		case !p.Enabled:
+			findings = append(findings, Finding{Level: levelError, Message: fmt.Sprintf("Firewall profile %s is disabled.", p.Name)})
+		case p.DefaultInbound == inboundAllow:
+			findings = append(findings, Finding{Level: levelWarn, Message: fmt.Sprintf("Firewall profile %s allows inbound connections by default.", p.Name)})
+		}
+	}
+
+	for _, rule := range fw.Rules {
+		if rule.From != anySource || rule.Proto == "udp" || (rule.Program != "" && rule.Ports == "") {
+			continue
+		}
+		var opened []string
+		for _, port := range remoteAccessPorts {
+			if !coversPort(rule.Ports, port) {
+				continue
+			}
+			name := "port " + strconv.Itoa(port)
+			if svc, ok := kb.lookup("tcp", port); ok {
+				name = fmt.Sprintf("%s (%d)", svc.Name, port)
+			}
+			opened = append(opened, name)
+		}
+		if len(opened) > 0 {
+			findings = append(findings, Finding{Level: levelError,
+				Message: fmt.Sprintf("Firewall rule %q allows %s from any source.", rule.Name, strings.Join(opened, ", "))})
+		}
+	}
+	return findings
+}
+
+// coversPort reports whether a port list such as "22", "80,443" or
+// "1000-2000" includes port. An empty list covers every port.
+func coversPort(spec string, port int) bool {
+	if spec == "" {
+		return true
+	}
+	for _, item := range strings.Split(spec, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(item), "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			continue
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				continue
+			}
+		}
+		if port >= first && port <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// inboundChain reports whether a chain filters incoming traffic: the
+// built-in input chain, or a chain named after it by front-ends such as
+// ufw ("ufw-user-input") and firewalld ("filter_IN_public_allow").
+func inboundChain(name string) bool {
+	return strings.Contains(strings.ToLower(name), "input") || strings.Contains(name, "_IN_")
+}
+
+// ufwAppPorts maps ufw application profiles to the ports they open.
+var ufwAppPorts = map[string]string{
+	"OpenSSH": "22", "Samba": "137,138,139,445", "CUPS": "631", "Apache": "80", "Nginx HTTP": "80",
+}
+
+// parseUFWStatus parses `ufw status verbose`:
+//
+//	Status: active
+//	Default: deny (incoming), allow (outgoing), disabled (routed)
+//
+//	To                         Action      From
+//	--                         ------      ----
+//	22/tcp                     ALLOW IN    Anywhere
+//	3389                       ALLOW IN    192.168.1.0/24
+//	OpenSSH (v6)               ALLOW IN    Anywhere (v6)
+//	80/tcp on eth0             ALLOW IN    Anywhere
+func parseUFWStatus(data string) FirewallStatus {
+	fw := FirewallStatus{Backend: "ufw"}
+	profile := FirewallProfile{Name: "ufw"}
+	found := false
+	inRules := false
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Status:"):
+			found = true
+			profile.Enabled = strings.TrimSpace(strings.TrimPrefix(line, "Status:")) == "active"
+		case strings.HasPrefix(line, "Default:"):
+			incoming, _, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "Default:")), " ")
+			profile.DefaultInbound = inboundBlock
+			if incoming == "allow" {
+				profile.DefaultInbound = inboundAllow
+			}
+		case strings.HasPrefix(line, "--"):
+			inRules = true
+		case inRules && line != "":
+			if rule, ok := parseUFWRule(line); ok {
+				fw.Rules = append(fw.Rules, rule)
+			}
+		}
+	}
+	if found {
+		fw.Profiles = []FirewallProfile{profile}
+	}
+	return fw
+}
+
+// ufwRulePattern splits a ufw rule line into target, action and source,
+// which are separated by runs of spaces.
+var ufwRulePattern = regexp.MustCompile(`^(.+?)\s{2,}(ALLOW|DENY|REJECT|LIMIT)(?: (IN|OUT|FWD))?\s+(.+)$`)
+
+// parseUFWRule parses one rule of `ufw status`; only inbound ALLOW and
+// LIMIT rules are returned.
+func parseUFWRule(line string) (FirewallRule, bool) {
+	m := ufwRulePattern.FindStringSubmatch(line)
+	if m == nil || (m[2] != "ALLOW" && m[2] != "LIMIT") || (m[3] != "" && m[3] != "IN") {
+		return FirewallRule{}, false
+	}
+	to := ufwAddress(m[1])
+	from := ufwAddress(m[4])
+	if from == "Anywhere" {
+		from = anySource
+	}
+	rule := FirewallRule{Name: strings.Join(strings.Fields(line), " "), Proto: "any", From: from}
+
+	// The target is "port[/proto]", "addr port[/proto]" or an app profile
+	if fields := strings.Fields(to); len(fields) == 2 && strings.ContainsAny(fields[0], ".:") {
+		to = fields[1]
+	}
+	ports, proto, _ := strings.Cut(to, "/")
+	if proto != "" {
+		rule.Proto = proto
+	}
+	switch {
+	case ufwAppPorts[ports] != "":
+		rule.Ports = ufwAppPorts[ports]
+	case ports == "Anywhere":
+	case ports[0] >= '0' && ports[0] <= '9':
+		rule.Ports = strings.ReplaceAll(ports, ":", "-")
+	default:
+		rule.Program = "ufw app " + ports // Unknown application profile
+	}
+	return rule, true
+}
+
+// ufwAddress strips the " (v6)" marker and the " on <iface>" qualifier
+// from a column of `ufw status`, e.g. "22/tcp (v6) on eth0" becomes
+// "22/tcp".
+func ufwAddress(column string) string {
+	column = strings.Replace(strings.TrimSpace(column), " (v6)", "", 1)
+	column, _, _ = strings.Cut(column, " on ")
+	return column
+}
+
+// nftChainPattern matches the "chain <name> {" line of `nft list ruleset`.
+var nftChainPattern = regexp.MustCompile(`^chain (\S+) \{$`)
+
+// nftPortsPattern matches "dport 22", "dport 1000-2000" or
+// "dport { 80, 443 }".
+var nftPortsPattern = regexp.MustCompile(`\b(tcp|udp|th) dport (\{[^}]*\}|\S+)`)
+
+// nftSourcePattern matches "ip saddr 10.0.0.0/8" or "ip6 saddr { ... }".
+var nftSourcePattern = regexp.MustCompile(`\bip6? saddr (\{[^}]*\}|\S+)`)
+
+// parseNftRuleset parses `nft list ruleset`. Every chain hooked on input
+// becomes a profile with its policy; accept rules of inbound chains (see
+// inboundChain) become rules.
+//
+//	table inet filter {
+//		chain input {
+//			type filter hook input priority filter; policy drop;
+//			ct state established,related accept
+//			ip saddr 10.0.0.0/8 tcp dport { 22, 3389 } accept
+//		}
+//	}
+func parseNftRuleset(data string) FirewallStatus {
+	fw := FirewallStatus{Backend: "nftables"}
+	var table, chain string
+	profile := -1
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "table "):
+			table = strings.TrimSuffix(strings.TrimPrefix(line, "table "), " {")
+		case nftChainPattern.MatchString(line):
+			chain = nftChainPattern.FindStringSubmatch(line)[1]
+			profile = -1
+		case line == "}":
+			chain, profile = "", -1
+		case chain == "":
+		case strings.Contains(line, "hook input"):
+			p := FirewallProfile{Name: table + " " + chain, Enabled: true, DefaultInbound: inboundAllow}
+			if strings.Contains(line, "policy drop") || strings.Contains(line, "policy reject") {
+				p.DefaultInbound = inboundBlock
+			}
+			fw.Profiles = append(fw.Profiles, p)
+			profile = len(fw.Profiles) - 1
+		case inboundChain(chain) || profile >= 0:
+			if rule, ok := parseNftRule(line); ok {
+				fw.Rules = append(fw.Rules, rule)
+			}
+		}
+	}
+	return fw
+}
+
+// parseNftRule parses one nftables rule; only accept rules for new
+// connections from other hosts are returned.
+func parseNftRule(line string) (FirewallRule, bool) {
+	rule := strings.TrimSpace(strings.Split(line, " comment ")[0])
+	if !strings.HasSuffix(rule, " accept") && rule != "accept" {
+		return FirewallRule{}, false
+	}
+	if established(rule) || strings.Contains(rule, `iif "lo"`) || strings.Contains(rule, `iifname "lo"`) {
+		return FirewallRule{}, false
+	}
+	r := FirewallRule{Name: line, Proto: "any", From: anySource}
+	if m := nftPortsPattern.FindStringSubmatch(rule); m != nil {
+		if m[1] != "th" {
+			r.Proto = m[1]
+		}
+		r.Ports = strings.ReplaceAll(strings.Trim(m[2], "{} "), " ", "")
+	} else if strings.HasPrefix(rule, "tcp ") || strings.HasPrefix(rule, "udp ") {
+		r.Proto = rule[:3]
+	}
+	if m := nftSourcePattern.FindStringSubmatch(rule); m != nil {
+		r.From = strings.ReplaceAll(strings.Trim(m[1], "{} "), " ", "")
+	}
+	return r, true
+}
+
+// established reports whether a rule only matches established or related
+// connections, which every stateful firewall accepts.
+func established(rule string) bool {
+	lower := strings.ToLower(rule)
+	return (strings.Contains(lower, "established") || strings.Contains(lower, "related")) && !strings.Contains(lower, "new")
+}
+
+// parseIptablesSave parses the filter table of `iptables-save`. The INPUT
+// chain becomes a profile with its policy; ACCEPT rules of inbound chains
+// (see inboundChain) become rules.
+//
+//	*filter
+//	:INPUT DROP [0:0]
+//	-A INPUT -p tcp -m tcp --dport 22 -j ACCEPT
+//	-A INPUT -s 10.0.0.0/8 -p tcp -m multiport --dports 80,443 -j ACCEPT
+//	COMMIT
+func parseIptablesSave(data string) FirewallStatus {
+	fw := FirewallStatus{Backend: "iptables"}
+	inFilter := false
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "*"):
+			inFilter = line == "*filter"
+		case !inFilter:
+		case strings.HasPrefix(line, ":INPUT "):
+			policy := strings.Fields(line)[1]
+			p := FirewallProfile{Name: "filter INPUT", Enabled: true, DefaultInbound: inboundAllow}
+			if policy == "DROP" || policy == "REJECT" {
+				p.DefaultInbound = inboundBlock
+			}
+			fw.Profiles = append(fw.Profiles, p)
+		case strings.HasPrefix(line, "-A "):
+			if rule, ok := parseIptablesRule(line); ok {
+				fw.Rules = append(fw.Rules, rule)
+			}
+		}
+	}
+	return fw
+}
+
+// parseIptablesRule parses one "-A chain ..." line; only ACCEPT rules of
+// inbound chains for new connections from other hosts are returned.
+func parseIptablesRule(line string) (FirewallRule, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !inboundChain(fields[1]) || !strings.HasSuffix(line, "-j ACCEPT") {
+		return FirewallRule{}, false
+	}
+	if established(line) {
+		return FirewallRule{}, false
+	}
+	rule := FirewallRule{Name: line, Proto: "any", From: anySource}
+	for i := 2; i+1 < len(fields); i++ {
+		switch fields[i] {
+		case "-i":
+			if fields[i+1] == "lo" {
+				return FirewallRule{}, false
+			}
+		case "-p":
+			rule.Proto = fields[i+1]
+		case "-s":
+			if fields[i+1] != "0.0.0.0/0" && fields[i+1] != "::/0" {
+				rule.From = fields[i+1]
+			}
+		case "--dport", "--dports":
+			rule.Ports = strings.ReplaceAll(fields[i+1], ":", "-")
+		}
+	}
+	return rule, true
+}
+
+// parseAdvfirewallProfiles parses `netsh advfirewall show allprofiles`:
+//
+//	Domain Profile Settings:
+//	----------------------------------------------------------------------
+//	State                                 ON
+//	Firewall Policy                       BlockInbound,AllowOutbound
+func parseAdvfirewallProfiles(data string) []FirewallProfile {
+	var profiles []FirewallProfile
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if name, ok := strings.CutSuffix(line, " Profile Settings:"); ok {
+			profiles = append(profiles, FirewallProfile{Name: name})
+			continue
+		}
+		if len(profiles) == 0 {
+			continue
+		}
+		p := &profiles[len(profiles)-1]
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "State":
+			p.Enabled = fields[1] == "ON"
+		case len(fields) == 3 && fields[0] == "Firewall" && fields[1] == "Policy":
+			p.DefaultInbound = inboundBlock
+			if strings.HasPrefix(fields[2], "AllowInbound") {
+				p.DefaultInbound = inboundAllow
+			}
+		}
+	}
+	return profiles
+}
+
+// parseAdvfirewallRules parses `netsh advfirewall firewall show rule
+// name=all dir=in verbose`, a block of "Key: value" lines per rule. Only
+// enabled inbound allow rules are returned.
+//
+//	Rule Name:                            Remote Desktop - User Mode (TCP-In)
+//	----------------------------------------------------------------------
+//	Enabled:                              Yes
+//	Direction:                            In
+//	RemoteIP:                             Any
+//	Protocol:                             TCP
+//	LocalPort:                            3389
+//	Program:                              %SystemRoot%\system32\svchost.exe
+//	Action:                               Allow
+func parseAdvfirewallRules(data string) []FirewallRule {
+	var rules []FirewallRule
+	var values map[string]string
+	flush := func() {
+		if values == nil || values["Enabled"] != "Yes" || values["Direction"] != "In" || values["Action"] != "Allow" {
+			return
+		}
+		rule := FirewallRule{Name: values["Rule Name"], Proto: strings.ToLower(values["Protocol"]), From: values["RemoteIP"]}
+		if rule.Proto == "" {
+			rule.Proto = "any"
+		}
+		if rule.From == "Any" || rule.From == "" {
+			rule.From = anySource
+		}
+		if port := values["LocalPort"]; port != "Any" {
+			rule.Ports = port
+		}
+		if program := values["Program"]; program != "" && program != "Any" {
+			rule.Program = program
+		}
+		rules = append(rules, rule)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "Rule Name" {
+			flush()
+			values = map[string]string{}
+		}
+		if values != nil {
+			values[key] = value
+		}
+	}
+	flush()
+	return rules
+}
+
+// socketFilterPrograms maps programs of the macOS application firewall to
+// the remote-access ports they serve.
+var socketFilterPrograms = map[string]string{
+	"sshd": "22", "sshd-keygen-wrapper": "22", "smbd": "445", "ftpd": "21", "ARDAgent": "3283,5900", "screensharingd": "5900",
+}
+
+// parseSocketFilterState parses the output of `socketfilterfw
+// --getglobalstate` and `--getblockall`. State 1 is on, 2 also blocks
+// everything but essential services.
+//
+//	Firewall is enabled. (State = 1)
+//	Firewall has block all state set to enabled.
+//
+// Older releases print "Firewall is set to block all non-essential
+// incoming connections" when block-all is on; the disabled message names
+// "block all" as well, so only the enabled forms count.
+func parseSocketFilterState(globalState, blockAll string) FirewallProfile {
+	p := FirewallProfile{Name: "Application Firewall", DefaultInbound: inboundAllow}
+	p.Enabled = strings.Contains(globalState, "enabled") || strings.Contains(globalState, "State = 1") || strings.Contains(globalState, "State = 2")
+	blockAll = strings.ToLower(blockAll)
+	blocking := strings.Contains(blockAll, "set to block all") ||
+		(strings.Contains(blockAll, "block all") && strings.Contains(blockAll, "enabled"))
+	if blocking || strings.Contains(globalState, "State = 2") {
+		p.DefaultInbound = inboundBlock
+	}
+	return p
+}
+
+// parseSocketFilterApps parses `socketfilterfw --listapps`. Apps allowed
+// incoming connections become rules; known servers get their ports.
+//
+//	1 :  /usr/libexec/sshd-keygen-wrapper
+//		 ( Allow incoming connections )
+func parseSocketFilterApps(data string) []FirewallRule {
+	var rules []FirewallRule
+	program := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if _, path, ok := strings.Cut(line, " :  "); ok {
+			program = strings.TrimSpace(path)
+			continue
+		}
+		if program != "" && strings.Contains(line, "Allow incoming connections") {
+			name := program[strings.LastIndex(program, "/")+1:]
+			name = strings.TrimSuffix(name, ".app")
+			rules = append(rules, FirewallRule{Name: name, Proto: "any", Ports: socketFilterPrograms[name], From: anySource, Program: program})
+			program = ""
+		}
+	}
+	return rules
+}
@@ -0,0 +1,20 @@
+package main
+
+import "strings"
+
+// socketFilterFW is the command-line tool of the application firewall.
+const socketFilterFW = "/usr/libexec/ApplicationFirewall/socketfilterfw"
+
+// readFirewall reads the state and the allowed apps of the application
+// firewall. A failing command is reported as a failed step.
+func readFirewall(r *checkRun) (FirewallStatus, error) {
+	capture := func(arg string) string {
+		return strings.Join(r.capture(Step{Command: socketFilterFW, Args: []string{arg}, Quiet: true}), "\n")
+	}
+	state, blockAll := capture("--getglobalstate"), capture("--getblockall")
+	return FirewallStatus{
+		Backend:  "socketfilterfw",
+		Profiles: []FirewallProfile{parseSocketFilterState(state, blockAll)},
+		Rules:    parseSocketFilterApps(capture("--listapps")),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// readFirewall reads ufw when it is enabled, and otherwise the nftables
+// rule set (which includes firewalld and iptables-nft rules) or
+// iptables-save. iptables-legacy rules do not show in the nftables rule
+// set, so an nftables rule set without an input chain falls back to
+// iptables-save. All of them need root.
+func readFirewall(r *checkRun) (FirewallStatus, error) {
+	if os.Geteuid() != 0 {
+		return FirewallStatus{}, errors.New("reading the firewall rules requires root; run CheckPoint with sudo")
+	}
+	if hasCommand("ufw") && strings.Contains(readTrimmed("/etc/ufw/ufw.conf"), "ENABLED=yes") {
+		out := r.capture(Step{Command: "ufw", Args: []string{"status", "verbose"}, Quiet: true})
+		return parseUFWStatus(strings.Join(out, "\n")), nil
+	}
+	var fw FirewallStatus
+	if hasCommand("nft") {
+		out := r.capture(Step{Command: "nft", Args: []string{"list", "ruleset"}, Quiet: true})
+		fw = parseNftRuleset(strings.Join(out, "\n"))
+		if len(fw.Profiles) > 0 {
+			return fw, nil
+		}
+	}
+	if hasCommand("iptables-save") {
+		if fw.Backend != "" {
+			r.log("[INFO] The nftables rule set has no input chain; reading iptables-save.")
+		}
+		out := r.capture(Step{Command: "iptables-save", Quiet: true})
+		return parseIptablesSave(strings.Join(out, "\n")), nil
+	}
+	return fw, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFirewallParsers(t *testing.T) {
+	rule := func(name, proto, ports, from string) FirewallRule {
+		return FirewallRule{Name: name, Proto: proto, Ports: ports, From: from}
+	}
+	tests := []struct {
+		fixture string
+		parse   func(string) FirewallStatus
+		want    FirewallStatus
+	}{
+		{"ufw_status.txt", parseUFWStatus, FirewallStatus{
+			Backend:  "ufw",
+			Profiles: []FirewallProfile{{Name: "ufw", Enabled: true, DefaultInbound: inboundBlock}},
+			Rules: []FirewallRule{
+				rule("22/tcp ALLOW IN Anywhere", "tcp", "22", anySource),
+				rule("OpenSSH LIMIT IN Anywhere", "any", "22", anySource),
+				rule("3389 ALLOW IN 192.168.1.0/24", "any", "3389", "192.168.1.0/24"),
+				rule("60000:61000/udp ALLOW IN Anywhere", "udp", "60000-61000", anySource),
+				rule("80/tcp on eth0 ALLOW IN Anywhere", "tcp", "80", anySource),
+				rule("Anywhere on wg0 ALLOW IN 10.8.0.0/24", "any", "", "10.8.0.0/24"),
+				rule("192.168.1.10 445/tcp ALLOW IN 192.168.1.0/24", "tcp", "445", "192.168.1.0/24"),
+				{Name: "Plex ALLOW IN Anywhere", Proto: "any", From: anySource, Program: "ufw app Plex"},
+				rule("22/tcp (v6) ALLOW IN Anywhere (v6)", "tcp", "22", anySource),
+				rule("80/tcp (v6) on eth0 ALLOW IN Anywhere (v6)", "tcp", "80", anySource),
+				rule("Anywhere (v6) on wg0 ALLOW IN fd00::/64", "any", "", "fd00::/64"),
+			},
+		}},
+		{"nft_ruleset.txt", parseNftRuleset, FirewallStatus{
+			Backend:  "nftables",
+			Profiles: []FirewallProfile{{Name: "inet filter input", Enabled: true, DefaultInbound: inboundBlock}},
+			Rules: []FirewallRule{
+				rule("ip protocol icmp accept", "any", "", anySource),
+				rule(`tcp dport 22 accept comment "ssh"`, "tcp", "22", anySource),
+				rule("ip saddr 10.0.0.0/8 tcp dport { 80, 443 } accept", "tcp", "80,443", "10.0.0.0/8"),
+				rule("ip6 saddr { fd00::/8, fe80::/10 } udp dport 5353 accept", "udp", "5353", "fd00::/8,fe80::/10"),
+				rule("udp dport 60000-61000 accept", "udp", "60000-61000", anySource),
+				rule("tcp dport 3389 ct state new,untracked accept", "tcp", "3389", anySource),
+			},
+		}},
+		{"iptables_save.txt", parseIptablesSave, FirewallStatus{
+			Backend:  "iptables",
+			Profiles: []FirewallProfile{{Name: "filter INPUT", Enabled: true, DefaultInbound: inboundBlock}},
+			Rules: []FirewallRule{
+				rule("-A INPUT -p tcp -m tcp --dport 22 -j ACCEPT", "tcp", "22", anySource),
+				rule("-A INPUT -s 10.0.0.0/8 -p tcp -m multiport --dports 80,443 -j ACCEPT", "tcp", "80,443", "10.0.0.0/8"),
+				rule("-A INPUT -p udp -m udp --dport 60000:61000 -j ACCEPT", "udp", "60000-61000", anySource),
+				rule("-A ufw-user-input -s 0.0.0.0/0 -p tcp --dport 3389 -j ACCEPT", "tcp", "3389", anySource),
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := tt.parse(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got.Profiles, tt.want.Profiles) || got.Backend != tt.want.Backend {
+				t.Errorf("got %s %+v, want %s %+v", got.Backend, got.Profiles, tt.want.Backend, tt.want.Profiles)
+			}
+			if !reflect.DeepEqual(got.Rules, tt.want.Rules) {
+				t.Errorf("got rules:\n%s\nwant:\n%s", formatFirewallRules(got.Rules), formatFirewallRules(tt.want.Rules))
+			}
+		})
+	}
+}
+
+func TestParseUFWRuleInterface(t *testing.T) {
+	tests := []struct {
+		line  string
+		proto string
+		ports string
+		from  string
+	}{
+		{"22/tcp on eth0             ALLOW IN    Anywhere", "tcp", "22", anySource},
+		{"Anywhere on eth0           ALLOW IN    Anywhere", "any", "", anySource},
+		{"OpenSSH on eth0            ALLOW IN    10.0.0.0/8", "any", "22", "10.0.0.0/8"},
+		{"1000:2000/udp (v6) on wg0  ALLOW IN    Anywhere (v6)", "udp", "1000-2000", anySource},
+	}
+	for _, tt := range tests {
+		rule, ok := parseUFWRule(tt.line)
+		if !ok || rule.Proto != tt.proto || rule.Ports != tt.ports || rule.From != tt.from || rule.Program != "" {
+			t.Errorf("parseUFWRule(%q) = %+v, %v; want %s ports %q from %q", tt.line, rule, ok, tt.proto, tt.ports, tt.from)
+		}
+	}
+}
+
+func TestAdvfirewallParsers(t *testing.T) {
+	wantProfiles := []FirewallProfile{
+		{Name: "Domain", Enabled: true, DefaultInbound: inboundBlock},
+		{Name: "Private", Enabled: true, DefaultInbound: inboundAllow},
+		{Name: "Public", Enabled: false, DefaultInbound: inboundBlock},
+	}
+	if got := parseAdvfirewallProfiles(readFixture(t, "advfirewall_profiles_windows.txt")); !reflect.DeepEqual(got, wantProfiles) {
+		t.Errorf("parseAdvfirewallProfiles = %+v, want %+v", got, wantProfiles)
+	}
+
+	// Disabled and block rules are skipped
+	wantRules := []FirewallRule{
+		{Name: "Remote Desktop - User Mode (TCP-In)", Proto: "tcp", Ports: "3389", From: anySource, Program: `%SystemRoot%\system32\svchost.exe`},
+		{Name: "File and Printer Sharing (SMB-In)", Proto: "tcp", Ports: "445", From: "LocalSubnet"},
+		{Name: "Zoom Meetings", Proto: "any", From: anySource, Program: `C:\Users\exam\AppData\Roaming\Zoom\bin\Zoom.exe`},
+	}
+	if got := parseAdvfirewallRules(readFixture(t, "advfirewall_rules_windows.txt")); !reflect.DeepEqual(got, wantRules) {
+		t.Errorf("got rules:\n%s\nwant:\n%s", formatFirewallRules(got), formatFirewallRules(wantRules))
+	}
+}
+
+func TestSocketFilterParsers(t *testing.T) {
+	state := readFixture(t, "socketfilterfw_globalstate_darwin.txt")
+	tests := []struct {
+		name     string
+		global   string
+		blockAll string
+		enabled  bool
+		inbound  string
+	}{
+		{"block all disabled", state, readFixture(t, "socketfilterfw_blockall_disabled_darwin.txt"), true, inboundAllow},
+		{"block all enabled", state, readFixture(t, "socketfilterfw_blockall_enabled_darwin.txt"), true, inboundBlock},
+		{"older block all enabled", state, "Firewall is set to block all non-essential incoming connections\n", true, inboundBlock},
+		{"essential services only", "Firewall is blocking all non-essential incoming connections. (State = 2)\n", "", true, inboundBlock},
+		{"firewall off", "Firewall is disabled. (State = 0)\n", readFixture(t, "socketfilterfw_blockall_disabled_darwin.txt"), false, inboundAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parseSocketFilterState(tt.global, tt.blockAll)
+			if p.Enabled != tt.enabled || p.DefaultInbound != tt.inbound {
+				t.Errorf("parseSocketFilterState = %+v, want enabled %v, inbound %s", p, tt.enabled, tt.inbound)
+			}
+		})
+	}
+
+	// Blocked apps are skipped; known servers get their ports
+	want := []FirewallRule{
+		{Name: "zoom.us", Proto: "any", From: anySource, Program: "/Applications/zoom.us.app"},
+		{Name: "sshd-keygen-wrapper", Proto: "any", Ports: "22", From: anySource, Program: "/usr/libexec/sshd-keygen-wrapper"},
+		{Name: "ARDAgent", Proto: "any", Ports: "3283,5900", From: anySource, Program: "/System/Library/CoreServices/RemoteManagement/ARDAgent.app"},
+	}
+	if got := parseSocketFilterApps(readFixture(t, "socketfilterfw_listapps_darwin.txt")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got rules:\n%s\nwant:\n%s", formatFirewallRules(got), formatFirewallRules(want))
+	}
+}
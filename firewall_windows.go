@@ -0,0 +1,15 @@
+package main
+
+import "strings"
+
+// readFirewall reads the profiles and the inbound rules of Windows
+// Defender Firewall. A failing command is reported as a failed step.
+func readFirewall(r *checkRun) (FirewallStatus, error) {
+	profiles := r.capture(Step{Command: "netsh", Args: []string{"advfirewall", "show", "allprofiles"}, Quiet: true})
+	rules := r.capture(Step{Command: "netsh", Args: []string{"advfirewall", "firewall", "show", "rule", "name=all", "dir=in", "verbose"}, Quiet: true})
+	return FirewallStatus{
+		Backend:  "netsh advfirewall",
+		Profiles: parseAdvfirewallProfiles(strings.Join(profiles, "\n")),
+		Rules:    parseAdvfirewallRules(strings.Join(rules, "\n")),
+	}, nil
+}
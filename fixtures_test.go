@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readFixture returns the contents of a captured command output or file in
+// testdata.
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return string(data)
+}
+
+// assertFindings compares findings with want, given in order as
+// "level: message substring".
+func assertFindings(t *testing.T, got []Finding, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings %v, want %d", len(got), got, len(want))
+	}
+	for i, f := range got {
+		level, msg, _ := strings.Cut(want[i], ": ")
+		if f.Level != level || !strings.Contains(f.Message, msg) {
+			t.Errorf("finding %d = %s: %s, want %s", i, f.Level, f.Message, want[i])
+		}
+	}
+}
@@ -1,66 +1,3 @@
-// Categories Configuration
-const categories = [
-    {
-        id: "network",
-        title: "Network",
-        icon: "📡",
-        tools: [
-            "Cek IP",
-            "Cek Routing",
-            "Netstat",
-            "ARP Table",
-            "Ping Connectivity"
-        ]
-    },
-    {
-        id: "application_system",
-        title: "Application / System",
-        icon: "💻",
-        tools: [
-            "System Information",
-            "Check installed applications",
-            "List PS Drives",
-            "Access HKLM Registry",
-            "Startup Services",
-            "Registry Check",
-            "Registry Editor",
-            "Task Manager"
-        ]
-    },
-    {
-        id: "malware_antivirus",
-        title: "Malware / Anti Virus",
-        icon: "🛡️",
-        tools: [
-            "Security Status",
-            "Protection Health",
-            "Run Quick Scan"
-        ]
-    },
-    {
-        id: "remote_services",
-        title: "Remote Services",
-        icon: "🔗",
-        tools: [
-            "Check active network service ports",
-            "Check installed browser extensions",
-            "Device Manager (Bluetooth)",
-            "Open Remote Access Settings"
-        ]
-    },
-    {
-        id: "clean_files",
-        title: "Clean Files",
-        icon: "🧹",
-        tools: [
-            "Run Full Cleanup",
-            "Open Temp Folder",
-            "Open Trash / Recycle Bin",
-            "Open Office Temp Files"
-        ]
-    }
-];
-
 // References
 const runtime = window.runtime;
 const toolsContainer = document.getElementById('toolsContainer');
@@ -68,39 +5,67 @@ const logContainer = document.getElementById('logContainer');
 const clearBtn = document.getElementById('clearLogsBtn');
 const exportBtn = document.getElementById('exportLogsBtn');
 const globalResetBtn = document.getElementById('globalResetBtn');
+const queueStatus = document.getElementById('queueStatus');
+const profileSelect = document.getElementById('profileSelect');
+const profileRunBtn = document.getElementById('profileRunBtn');
+const profileProgress = document.getElementById('profileProgress');
 
 // State Tracking
-// Format: { "toolName": { button: HTMLElement, state: 'idle'|'running'|'done' } }
+// Format: { "checkId": { button: HTMLElement, state: 'idle'|'queued'|'running'|'done', runId: string|null,
+//            cancelPending: 'stop'|'reset'|null } }
+// cancelPending records a Stop or Reset made before the run ID was known.
 const toolControls = {};
 let logBuffer = "";
+let activeProfileRun = null;
+// Runs cancelled by Reset; their late events must not touch the reset UI
+const resetRuns = new Set();
 
 // --- Logging ---
 
-function appendLog(message, isRaw = false) {
+// Colours per run event level
+const levelColors = {
+    header: '#86868B', // Secondary color for headers
+    error: '#FF453A',  // Red for errors
+    warn: '#FF9F0A',   // Orange for warnings, cancellations
+    ok: '#32D74B'      // Green for success
+};
+
+function appendLog(message, level = 'info', meta = null) {
     if (!message) return; // Ignore empty strings if any
     logBuffer += message + "\n";
     const entry = document.createElement('div');
-    entry.className = 'log-line';
-    if (!isRaw) {
-        if (message.startsWith('===')) {
-            entry.style.color = '#86868B'; // Secondary color for headers
-        }
-        else if (message.startsWith('[ERROR]')) {
-            entry.style.color = '#FF453A'; // Red for errors
-        }
-        else if (message.startsWith('[OK]')) {
-            entry.style.color = '#32D74B'; // Green for success
-        }
+    entry.className = `log-line level-${level}`;
+    if (levelColors[level]) {
+        entry.style.color = levelColors[level];
+    }
+    if (meta) {
+        entry.dataset.runId = meta.runId;
+        entry.dataset.checkId = meta.checkId;
+        entry.dataset.stream = meta.stream;
     }
     entry.textContent = message;
     logContainer.appendChild(entry);
     logContainer.scrollTop = logContainer.scrollHeight;
 }
 
+// Render a structured run event from the backend
+function appendRunEvent(ev) {
+    let text = ev.text;
+    if (ev.stream === 'stderr') text = `[ERR] ${text}`;
+    if (ev.stepLabel) text = `[${ev.stepLabel}] ${text}`;
+    appendLog(text, ev.level, ev);
+}
+
 // --- Initialization ---
 
-function init() {
-    categories.forEach(cat => {
+function backendAvailable(method) {
+    return window.go && window.go.main && window.go.main.App && window.go.main.App[method];
+}
+
+function renderCatalog(catalog) {
+    toolsContainer.innerHTML = '';
+
+    catalog.categories.forEach(cat => {
         // Create Category Block
         const block = document.createElement('div');
         block.className = 'category-block expanded'; // Default expanded
@@ -124,44 +89,97 @@ function init() {
         const body = document.createElement('div');
         body.className = 'category-body';
 
-        cat.tools.forEach(toolName => {
+        cat.checks.forEach(check => {
             const row = document.createElement('div');
             row.className = 'tool-row';
-
-            // Clean ID for tool
-            const toolId = toolName.replace(/\s+/g, '-').replace(/[^a-zA-Z0-9-]/g, '').toLowerCase();
+            if (!check.supported) {
+                row.classList.add('unsupported');
+                row.title = check.reason;
+            } else if (check.description) {
+                const source = check.custom ? ', from checkpoint-checks.json' : '';
+                row.title = `${check.description} (ID: ${check.id}, timeout: ${check.timeoutSeconds}s${source})`;
+            }
 
             row.innerHTML = `
                 <div class="tool-info">
-                    <div class="tool-name">${toolName}</div>
+                    <div class="tool-name"></div>
+                    <div class="tool-desc"></div>
                 </div>
-                <button class="run-btn" id="btn-${toolId}">Run</button>
             `;
+            const btn = document.createElement('button');
+            btn.className = 'run-btn';
+            btn.id = `btn-${check.id}`;
+            btn.textContent = 'Run';
+            row.appendChild(btn);
+            row.querySelector('.tool-name').textContent = check.name;
+            row.querySelector('.tool-desc').textContent = check.supported ? check.description : check.reason;
 
             body.appendChild(row);
 
             // Bind State
-            const btn = row.querySelector(`#btn-${toolId}`);
-            btn.onclick = () => runTool(toolName, btn);
+            if (!check.supported) {
+                btn.textContent = 'N/A';
+                btn.disabled = true;
+                return;
+            }
+            btn.onclick = () => runTool(check.id, btn);
 
-            toolControls[toolName] = { button: btn, state: 'idle' };
+            if (check.id === 'hosts-file' && backendAvailable('ResetHostsBaseline')) {
+                const rebaseline = document.createElement('button');
+                rebaseline.className = 'run-btn';
+                rebaseline.textContent = 'Re-baseline';
+                rebaseline.title = 'Record the current hosts file as the baseline of this machine';
+                rebaseline.onclick = resetHostsBaseline;
+                row.insertBefore(rebaseline, btn);
+            }
+
+            toolControls[check.id] = { button: btn, state: 'idle', runId: null, cancelPending: null };
         });
 
         block.appendChild(body);
         toolsContainer.appendChild(block);
     });
+}
+
+function renderProfiles(profiles) {
+    profileSelect.innerHTML = '';
+    profiles.forEach(p => {
+        const opt = document.createElement('option');
+        opt.value = p.id;
+        opt.textContent = `${p.name} (${p.checks.length})`;
+        opt.title = p.description || '';
+        profileSelect.appendChild(opt);
+    });
+    profileRunBtn.disabled = profiles.length === 0;
+}
+
+function init() {
+    if (backendAvailable('GetProfiles')) {
+        window.go.main.App.GetProfiles().then(renderProfiles).catch(err => {
+            appendLog(`[ERROR] Failed to load profiles: ${err}`, 'error');
+        });
+    }
+    profileRunBtn.onclick = runProfile;
+
+    if (backendAvailable('GetCatalog')) {
+        window.go.main.App.GetCatalog().then(renderCatalog).catch(err => {
+            appendLog(`[ERROR] Failed to load check catalog: ${err}`, 'error');
+        });
+    } else {
+        appendLog('[ERROR] Backend unavailable: check catalog could not be loaded.', 'error');
+    }
 
     // Global Events
     clearBtn.onclick = () => {
         logContainer.innerHTML = '';
         logBuffer = "";
-        appendLog('--- Console Cleared ---', true);
+        appendLog('--- Console Cleared ---', 'header');
     };
 
     exportBtn.onclick = () => {
         if (window.go && window.go.main && window.go.main.App && window.go.main.App.ExportLogs) {
             window.go.main.App.ExportLogs(logBuffer).catch(err => {
-                appendLog(`[ERROR] Save failed: ${err}`);
+                appendLog(`[ERROR] Save failed: ${err}`, 'error');
             });
         }
     };
@@ -171,7 +189,12 @@ function init() {
     // Wails Events
     if (runtime) {
         runtime.EventsOn("log", (msg) => appendLog(msg));
-        runtime.EventsOn("done", (featureName) => handleDone(featureName));
+        runtime.EventsOn("run:event", (ev) => appendRunEvent(ev));
+        runtime.EventsOn("done", (evt) => handleDone(evt));
+        runtime.EventsOn("run:queued", (evt) => handleQueued(evt));
+        runtime.EventsOn("run:started", (evt) => handleStarted(evt));
+        runtime.EventsOn("profile:progress", (evt) => handleProfileProgress(evt));
+        runtime.EventsOn("profile:done", (summary) => handleProfileDone(summary));
 
         // Fetch Version
         if (window.go && window.go.main && window.go.main.App && window.go.main.App.GetAppVersion) {
@@ -186,50 +209,244 @@ function init() {
 
 // --- Logic ---
 
-function runTool(toolName, btn) {
+function resetHostsBaseline() {
+    window.go.main.App.ResetHostsBaseline().then(file => {
+        appendLog(`[INFO] Recorded the current hosts file as the baseline in ${file}`);
+    }).catch(err => {
+        appendLog(`[ERROR] Re-baseline failed: ${err}`, 'error');
+    });
+}
+
+function runTool(checkId, btn) {
+    const ctrl = toolControls[checkId];
+    if (ctrl && (ctrl.state === 'running' || ctrl.state === 'queued')) {
+        cancelTool(ctrl);
+        return;
+    }
     if (btn.disabled) return;
 
     // UI Update
-    btn.textContent = 'Running...';
+    btn.textContent = 'Stop';
+    btn.title = 'Running... click to stop';
     btn.classList.add('running');
-    btn.disabled = true;
+    if (ctrl) {
+        ctrl.state = 'running';
+        ctrl.runId = null;
+        ctrl.cancelPending = null;
+    }
 
     // Execute
-    if (window.go && window.go.main && window.go.main.App && window.go.main.App.ExecuteCommand) {
-        window.go.main.App.ExecuteCommand(toolName);
+    if (backendAvailable('ExecuteCommand')) {
+        window.go.main.App.ExecuteCommand(checkId).then(runId => {
+            // The run may already have finished (e.g. unsupported)
+            if (!ctrl || !(ctrl.cancelPending === 'reset' || ctrl.state === 'running' || ctrl.state === 'queued')) return;
+            if (adoptRun(ctrl, runId)) flushCancel(ctrl);
+        }).catch(err => {
+            appendLog(`[ERROR] ${err}`, 'error');
+            handleDone({ checkId: checkId, status: 'failed' });
+        });
     } else {
         // Mock (Fallback if backend unreachable in dev)
-        appendLog(`[MOCK] Starting ${toolName}...`);
-        setTimeout(() => handleDone(toolName), 1000);
+        appendLog(`[MOCK] Starting ${checkId}...`);
+        setTimeout(() => handleDone({ checkId: checkId, status: 'ok' }), 1000);
     }
 }
 
-function handleDone(featureName) {
-    // Note: featureName coming from backend must match the tool name exactly
-    const ctrl = toolControls[featureName];
-    if (ctrl) {
+function cancelTool(ctrl) {
+    if (!backendAvailable('CancelRun')) return;
+    ctrl.button.textContent = 'Stopping...';
+    ctrl.button.disabled = true;
+    if (!ctrl.runId) {
+        // ExecuteCommand has not returned yet; cancel once the ID arrives
+        ctrl.cancelPending = 'stop';
+        return;
+    }
+    window.go.main.App.CancelRun(ctrl.runId).catch(err => {
+        appendLog(`[ERROR] Cancel failed: ${err}`, 'error');
+    });
+}
+
+// Record the ID of a button's run once it is known, from ExecuteCommand or
+// the first run event. A run reset before then is cancelled instead, and
+// false is returned so that it does not touch the reset UI.
+function adoptRun(ctrl, runId) {
+    if (resetRuns.has(runId)) return false;
+    if (ctrl.cancelPending === 'reset') {
+        ctrl.cancelPending = null;
+        resetRuns.add(runId);
+        window.go.main.App.CancelRun(runId);
+        return false;
+    }
+    ctrl.runId = runId;
+    return true;
+}
+
+// Send a Stop that was clicked before the run ID was known
+function flushCancel(ctrl) {
+    if (ctrl.cancelPending === 'stop' && ctrl.runId) {
+        ctrl.cancelPending = null;
+        cancelTool(ctrl);
+    }
+}
+
+// Put a button into the active state (also used for runs started by profiles)
+function markActive(ctrl) {
+    ctrl.button.disabled = false;
+    ctrl.button.classList.remove('done', 'cancelled', 'timeout', 'error');
+    ctrl.button.classList.add('running');
+}
+
+function handleQueued(evt) {
+    const ctrl = toolControls[evt.checkId];
+    if (ctrl && adoptRun(ctrl, evt.runId)) {
+        markActive(ctrl);
+        ctrl.state = 'queued';
+        ctrl.button.textContent = `Queued #${evt.position}`;
+        ctrl.button.title = 'Waiting for a free slot... click to cancel';
+        flushCancel(ctrl);
+    }
+    updateQueueStatus();
+}
+
+function handleStarted(evt) {
+    const ctrl = toolControls[evt.checkId];
+    if (ctrl && adoptRun(ctrl, evt.runId)) {
+        markActive(ctrl);
+        ctrl.state = 'running';
+        ctrl.button.textContent = 'Stop';
+        ctrl.button.title = 'Running... click to stop';
+        flushCancel(ctrl);
+    }
+    updateQueueStatus();
+}
+
+// --- Profiles ---
+
+function runProfile() {
+    if (activeProfileRun) {
+        if (backendAvailable('CancelRun')) {
+            profileRunBtn.textContent = 'Stopping...';
+            profileRunBtn.disabled = true;
+            window.go.main.App.CancelRun(activeProfileRun).catch(err => {
+                appendLog(`[ERROR] Cancel failed: ${err}`, 'error');
+            });
+        }
+        return;
+    }
+    if (!profileSelect.value || !backendAvailable('RunProfile')) return;
+
+    profileRunBtn.textContent = 'Stop';
+    profileRunBtn.classList.add('running');
+    profileSelect.disabled = true;
+    profileProgress.textContent = 'Starting...';
+    window.go.main.App.RunProfile(profileSelect.value).then(id => {
+        activeProfileRun = id;
+    }).catch(err => {
+        appendLog(`[ERROR] ${err}`, 'error');
+        resetProfileBar();
+    });
+}
+
+function handleProfileProgress(evt) {
+    if (resetRuns.has(evt.profileRunId)) return;
+    activeProfileRun = evt.profileRunId;
+    const ctrl = toolControls[evt.checkId];
+    const name = ctrl ? ctrl.button.closest('.tool-row').querySelector('.tool-name').textContent : evt.checkId;
+    profileProgress.textContent = `${evt.index}/${evt.total} · ${name}`;
+}
+
+function handleProfileDone(summary) {
+    if (resetRuns.delete(summary.profileRunId)) return;
+    const parts = Object.entries(summary.counts).map(([status, n]) => `${n} ${status}`);
+    resetProfileBar();
+    profileProgress.textContent = `${summary.profileName}: ${parts.join(', ')}`;
+}
+
+function resetProfileBar() {
+    activeProfileRun = null;
+    profileRunBtn.textContent = 'Run all';
+    profileRunBtn.classList.remove('running');
+    profileRunBtn.disabled = profileSelect.options.length === 0;
+    profileSelect.disabled = false;
+    profileProgress.textContent = '';
+}
+
+// Show how many checks are running and waiting
+function updateQueueStatus() {
+    const states = Object.values(toolControls).map(c => c.state);
+    const running = states.filter(s => s === 'running').length;
+    const queued = states.filter(s => s === 'queued').length;
+    queueStatus.textContent = (running || queued) ? `Running: ${running} · Queued: ${queued}` : '';
+}
+
+function handleDone(evt) {
+    if (resetRuns.delete(evt.runId)) {
+        updateQueueStatus();
+        return;
+    }
+    // Note: checkId coming from backend must match the catalog ID exactly
+    const ctrl = toolControls[evt.checkId];
+    if (ctrl && ctrl.cancelPending === 'reset') {
+        // Finished before its ID arrived, and already reset
+        ctrl.cancelPending = null;
+    } else if (ctrl) {
         ctrl.state = 'done';
-        ctrl.button.textContent = 'Done';
+        ctrl.runId = null;
+        ctrl.cancelPending = null;
+        ctrl.button.title = '';
         ctrl.button.classList.remove('running');
-        ctrl.button.classList.add('done');
+        if (evt.status === 'cancelled') {
+            ctrl.button.textContent = 'Cancelled';
+            ctrl.button.classList.add('cancelled');
+        } else if (evt.status === 'timeout') {
+            ctrl.button.textContent = 'Timed out';
+            ctrl.button.classList.add('timeout');
+        } else if (evt.status === 'failed' || evt.status === 'unsupported') {
+            ctrl.button.textContent = 'Failed';
+            ctrl.button.classList.add('error');
+        } else {
+            ctrl.button.textContent = 'Done';
+            ctrl.button.classList.add('done');
+        }
         // Keep disabled as per "persistent done state" requirement
         ctrl.button.disabled = true;
     } else {
-        console.warn(`[Frontend] Received done event for unknown feature: ${featureName}`);
+        console.warn(`[Frontend] Received done event for unknown check: ${evt.checkId}`);
     }
+    updateQueueStatus();
 }
 
 function resetAll() {
+    if (activeProfileRun && backendAvailable('CancelRun')) {
+        resetRuns.add(activeProfileRun);
+        window.go.main.App.CancelRun(activeProfileRun);
+    }
+    resetProfileBar();
+
     // Reset all tool buttons
     Object.keys(toolControls).forEach(key => {
         const ctrl = toolControls[key];
+        ctrl.cancelPending = null;
+        if (ctrl.state === 'running' || ctrl.state === 'queued') {
+            // The done event of the cancelled run arrives later and is ignored
+            if (ctrl.runId) {
+                resetRuns.add(ctrl.runId);
+                cancelTool(ctrl);
+            } else {
+                ctrl.cancelPending = 'reset';
+            }
+        }
         ctrl.state = 'idle';
+        ctrl.runId = null;
         ctrl.button.textContent = 'Run';
-        ctrl.button.classList.remove('running', 'done');
+        ctrl.button.title = '';
+        ctrl.button.classList.remove('running', 'done', 'cancelled', 'timeout');
         ctrl.button.classList.remove('error'); // If we had error state
         ctrl.button.disabled = false;
     });
 
+    updateQueueStatus();
+
     // Clear logs
     logContainer.innerHTML = '';
     logBuffer = "";
@@ -1,8 +1,25 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
+
+export function CancelRun(arg1:string):Promise<void>;
 
 export function ExecuteCommand(arg1:string):Promise<string>;
 
 export function ExportLogs(arg1:string):Promise<void>;
 
 export function GetAppVersion():Promise<string>;
+
+export function GetCatalog():Promise<main.Catalog>;
+
+export function GetProfiles():Promise<Array<main.Profile>>;
+
+export function GetRunResults():Promise<Array<main.RunResult>>;
+
+export function GetSettings():Promise<main.Settings>;
+
+export function ResetHostsBaseline():Promise<string>;
+
+export function RunProfile(arg1:string):Promise<string>;
+
+export function SaveSettings(arg1:main.Settings):Promise<void>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelRun(arg1) {
+  return window['go']['main']['App']['CancelRun'](arg1);
+}
+
 export function ExecuteCommand(arg1) {
   return window['go']['main']['App']['ExecuteCommand'](arg1);
 }
@@ -13,3 +17,31 @@ export function ExportLogs(arg1) {
 export function GetAppVersion() {
   return window['go']['main']['App']['GetAppVersion']();
 }
+
+export function GetCatalog() {
+  return window['go']['main']['App']['GetCatalog']();
+}
+
+export function GetProfiles() {
+  return window['go']['main']['App']['GetProfiles']();
+}
+
+export function GetRunResults() {
+  return window['go']['main']['App']['GetRunResults']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function ResetHostsBaseline() {
+  return window['go']['main']['App']['ResetHostsBaseline']();
+}
+
+export function RunProfile(arg1) {
+  return window['go']['main']['App']['RunProfile'](arg1);
+}
+
+export function SaveSettings(arg1) {
+  return window['go']['main']['App']['SaveSettings'](arg1);
+}
@@ -0,0 +1,395 @@
+export namespace main {
+	
+	export class CatalogCheck {
+	    id: string;
+	    name: string;
+	    description: string;
+	    opensWindow: boolean;
+	    destructive: boolean;
+	    exclusive: boolean;
+	    custom: boolean;
+	    supported: boolean;
+	    timeoutSeconds: number;
+	    reason?: string;
+	    platforms: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CatalogCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.opensWindow = source["opensWindow"];
+	        this.destructive = source["destructive"];
+	        this.exclusive = source["exclusive"];
+	        this.custom = source["custom"];
+	        this.supported = source["supported"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.reason = source["reason"];
+	        this.platforms = source["platforms"];
+	    }
+	}
+	export class CatalogCategory {
+	    id: string;
+	    title: string;
+	    icon: string;
+	    checks: CatalogCheck[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CatalogCategory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.icon = source["icon"];
+	        this.checks = this.convertValues(source["checks"], CatalogCheck);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Catalog {
+	    platform: string;
+	    categories: CatalogCategory[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Catalog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.platform = source["platform"];
+	        this.categories = this.convertValues(source["categories"], CatalogCategory);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TracerouteSettings {
+	    target?: string;
+	    maxHops?: number;
+	    hopTimeoutMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TracerouteSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.maxHops = source["maxHops"];
+	        this.hopTimeoutMs = source["hopTimeoutMs"];
+	    }
+	}
+	export class ConnectivityProbe {
+	    type: string;
+	    target: string;
+	    resolver?: string;
+	    timeoutMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectivityProbe(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.target = source["target"];
+	        this.resolver = source["resolver"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+	export class ConnectivitySettings {
+	    probes?: ConnectivityProbe[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectivitySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.probes = this.convertValues(source["probes"], ConnectivityProbe);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PortScanSettings {
+	    target?: string;
+	    ports?: string;
+	    udpPorts?: string;
+	    timeoutMs?: number;
+	    workers?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PortScanSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.ports = source["ports"];
+	        this.udpPorts = source["udpPorts"];
+	        this.timeoutMs = source["timeoutMs"];
+	        this.workers = source["workers"];
+	    }
+	}
+	export class TLSSettings {
+	    targets?: string[];
+	    warnDays?: number;
+	    timeoutMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.targets = source["targets"];
+	        this.warnDays = source["warnDays"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+	export class DNSSettings {
+	    allowedResolvers?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DNSSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.allowedResolvers = source["allowedResolvers"];
+	    }
+	}
+	export class ProxySettings {
+	    allowedHosts?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.allowedHosts = source["allowedHosts"];
+	    }
+	}
+	export class Settings {
+	    timeouts?: Record<string, number>;
+	    maxConcurrentRuns?: number;
+	    traceroute: TracerouteSettings;
+	    connectivity: ConnectivitySettings;
+	    portScan: PortScanSettings;
+	    tls: TLSSettings;
+	    dns: DNSSettings;
+	    proxy: ProxySettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timeouts = source["timeouts"];
+	        this.maxConcurrentRuns = source["maxConcurrentRuns"];
+	        this.traceroute = this.convertValues(source["traceroute"], TracerouteSettings);
+	        this.connectivity = this.convertValues(source["connectivity"], ConnectivitySettings);
+	        this.portScan = this.convertValues(source["portScan"], PortScanSettings);
+	        this.tls = this.convertValues(source["tls"], TLSSettings);
+	        this.dns = this.convertValues(source["dns"], DNSSettings);
+	        this.proxy = this.convertValues(source["proxy"], ProxySettings);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StepResult {
+	    index: number;
+	    label?: string;
+	    command: string;
+	    args?: string[];
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    durationMs: number;
+	    exitCode: number;
+	    error?: string;
+	    stdoutLines: number;
+	    stderrLines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StepResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.label = source["label"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.durationMs = source["durationMs"];
+	        this.exitCode = source["exitCode"];
+	        this.error = source["error"];
+	        this.stdoutLines = source["stdoutLines"];
+	        this.stderrLines = source["stderrLines"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RunResult {
+	    runId: string;
+	    checkId: string;
+	    checkName: string;
+	    status: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    durationMs: number;
+	    steps: StepResult[];
+	    stdoutLines: number;
+	    stderrLines: number;
+	    data?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RunResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.runId = source["runId"];
+	        this.checkId = source["checkId"];
+	        this.checkName = source["checkName"];
+	        this.status = source["status"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.durationMs = source["durationMs"];
+	        this.steps = this.convertValues(source["steps"], StepResult);
+	        this.stdoutLines = source["stdoutLines"];
+	        this.stderrLines = source["stderrLines"];
+	        this.data = source["data"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Profile {
+	    id: string;
+	    name: string;
+	    description?: string;
+	    checks: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Profile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.checks = source["checks"];
+	    }
+	}
+
+}
+
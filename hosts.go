@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// hostsBaselineFileName is stored next to the executable (see
+// getAppBaseDir). It keeps one hosts file baseline per machine, as the
+// application is portable.
+const hostsBaselineFileName = "checkpoint-hosts.json"
+
+// hostsBaselineMu serialises reads and writes of the baseline file.
+var hostsBaselineMu sync.Mutex
+
+// HostsEntry is one host name mapping of the hosts file.
+type HostsEntry struct {
+	IP       string `json:"ip"`
+	Hostname string `json:"hostname"` // Lower-case
+	Line     int    `json:"line"`
+}
+
+// Kinds of HostsChange.
+const (
+	hostsAdded   = "added"
+	hostsRemoved = "removed"
+	hostsChanged = "changed"
+)
+
+// HostsChange is a host name whose mapping differs from the baseline.
+type HostsChange struct {
+	Kind     string   `json:"kind"` // added, removed or changed
+	Hostname string   `json:"hostname"`
+	Old      []string `json:"old,omitempty"` // Addresses in the baseline
+	New      []string `json:"new,omitempty"` // Addresses now
+}
+
+// HostsAudit is the structured output of the "Hosts File Integrity" check.
+type HostsAudit struct {
+	Path     string        `json:"path"`
+	Entries  []HostsEntry  `json:"entries"`
+	Baseline time.Time     `json:"baseline"` // When the baseline was recorded
+	Changes  []HostsChange `json:"changes"`
+	Findings []Finding     `json:"findings"`
+}
+
+// hostsBaseline is the recorded hosts file of one machine.
+type hostsBaseline struct {
+	Recorded time.Time    `json:"recorded"`
+	Path     string       `json:"path"`
+	Entries  []HostsEntry `json:"entries"`
+}
+
+// hostsBaselineFile is the content of the baseline file, keyed by host name.
+type hostsBaselineFile struct {
+	Machines map[string]hostsBaseline `json:"machines"`
+}
+
+// sensitiveDomains are exam, learning-platform, security-vendor and major
+// service domains. Redirecting one lets a local server impersonate the
+// exam or the service, or keeps security software from updating. It is
+// the one list behind both the hosts file and the DNS checks. Subdomains
+// match as well.
+var sensitiveDomains = []string{
+	// Exam and learning platforms
+	"moodle.org", "moodlecloud.com", "instructure.com", "canvaslms.com", "blackboard.com",
+	"classroom.google.com", "forms.gle", "docs.google.com",
+	"examsoft.com", "proctorio.com", "respondus.com", "honorlock.com", "proctoru.com",
+	"examity.com", "pearsonvue.com", "prometric.com", "ets.org", "collegeboard.org",
+	"safeexambrowser.org",
+	// Security vendors and OS updates
+	"windowsupdate.com", "update.microsoft.com", "wdcp.microsoft.com", "smartscreen.microsoft.com",
+	"symantec.com", "norton.com", "mcafee.com", "kaspersky.com", "eset.com", "avast.com", "avg.com",
+	"bitdefender.com", "sophos.com", "trendmicro.com", "malwarebytes.com", "crowdstrike.com",
+	"sentinelone.com", "f-secure.com", "clamav.net", "virustotal.com",
+	// Major services a hosts file has no business overriding
+	"google.com", "googleapis.com", "gstatic.com", "youtube.com",
+	"microsoft.com", "live.com", "office.com", "microsoftonline.com",
+	"apple.com", "icloud.com", "mozilla.org", "github.com",
+	"cloudflare.com", "amazon.com", "facebook.com", "wikipedia.org",
+}
+
+// sensitiveSuffixes are education top-level and second-level domains.
+var sensitiveSuffixes = []string{"edu", "ac.id", "sch.id", "ac.uk", "edu.au"}
+
+// checkHostsFile implements "Hosts File Integrity": it compares the hosts
+// file with the baseline recorded on this machine and flags redirects of
+// sensitive domains. The first run records the baseline;
+// ResetHostsBaseline records a new one.
+func checkHostsFile(r *checkRun) {
+	path := hostsFilePath()
+	r.header("Status : Reading hosts file...", "Path : "+path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read hosts file: %v", err))
+		return
+	}
+	audit := HostsAudit{Path: path, Entries: parseHostsFile(string(data))}
+	if audit.Entries == nil {
+		audit.Entries = []HostsEntry{}
+	}
+	r.log(formatHostsEntries(audit.Entries))
+
+	var baseline hostsBaseline
+	var created bool
+	baseDir, err := r.app.getAppBaseDir()
+	if err == nil {
+		r.log(fmt.Sprintf("\n[INFO] Baseline file: %s", filepath.Join(baseDir, hostsBaselineFileName)))
+		baseline, created, err = loadHostsBaseline(baseDir, path, audit.Entries, time.Now())
+	}
+	if err != nil {
+		r.log(fmt.Sprintf("[WARN] Baseline not available: %v", err))
+	} else if created {
+		r.log("[INFO] No baseline for this machine; recorded the current hosts file.")
+	} else {
+		audit.Baseline = baseline.Recorded
+		audit.Changes = diffHosts(baseline.Entries, audit.Entries)
+		r.log(fmt.Sprintf("\n[ Changes since %s ]", baseline.Recorded.Format("2006-01-02 15:04")))
+		r.log(formatHostsChanges(audit.Changes))
+	}
+
+	audit.Findings = analyzeHostsChanges(audit.Changes)
+	_, sensitive := analyzeSensitiveHosts(audit.Entries)
+	audit.Findings = append(audit.Findings, sensitive...)
+	r.setData("hosts", audit)
+	if len(audit.Findings) == 0 {
+		r.log("[OK] The hosts file matches the baseline and redirects no sensitive domain.")
+	}
+	r.report(audit.Findings)
+}
+
+// loadHostsBaseline returns the baseline of this machine from the baseline
+// file in baseDir. Without one, entries (read from path) become the
+// baseline and created is true.
+func loadHostsBaseline(baseDir, path string, entries []HostsEntry, now time.Time) (baseline hostsBaseline, created bool, err error) {
+	hostsBaselineMu.Lock()
+	defer hostsBaselineMu.Unlock()
+
+	file := filepath.Join(baseDir, hostsBaselineFileName)
+	state, err := readHostsBaselines(file)
+	if err != nil {
+		return hostsBaseline{}, false, err
+	}
+	machine, _ := os.Hostname()
+	if baseline, ok := state.Machines[machine]; ok {
+		return baseline, false, nil
+	}
+
+	baseline = hostsBaseline{Recorded: now, Path: path, Entries: entries}
+	state.Machines[machine] = baseline
+	return baseline, true, writeHostsBaselines(file, state)
+}
+
+// ResetHostsBaseline records the current hosts file as the baseline of this
+// machine, replacing the previous one, and returns the baseline file.
+func (a *App) ResetHostsBaseline() (string, error) {
+	baseDir, err := a.getAppBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, hostsBaselineFileName), resetHostsBaseline(baseDir, hostsFilePath(), time.Now())
+}
+
+// resetHostsBaseline records the hosts file at path as the baseline of this
+// machine in the baseline file in baseDir.
+func resetHostsBaseline(baseDir, path string, now time.Time) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read hosts file: %w", err)
+	}
+	entries := parseHostsFile(string(data))
+	if entries == nil {
+		entries = []HostsEntry{}
+	}
+
+	hostsBaselineMu.Lock()
+	defer hostsBaselineMu.Unlock()
+
+	file := filepath.Join(baseDir, hostsBaselineFileName)
+	state, err := readHostsBaselines(file)
+	if err != nil {
+		return err
+	}
+	machine, _ := os.Hostname()
+	state.Machines[machine] = hostsBaseline{Recorded: now, Path: path, Entries: entries}
+	return writeHostsBaselines(file, state)
+}
+
+// readHostsBaselines returns the content of the baseline file. A missing
+// file yields no baselines. The caller holds hostsBaselineMu.
+func readHostsBaselines(file string) (hostsBaselineFile, error) {
+	state := hostsBaselineFile{Machines: map[string]hostsBaseline{}}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid %s: %w", hostsBaselineFileName, err)
+	}
+	if state.Machines == nil {
+		state.Machines = map[string]hostsBaseline{}
+	}
+	return state, nil
+}
+
+// writeHostsBaselines replaces the baseline file. The caller holds
+// hostsBaselineMu.
+func writeHostsBaselines(file string, state hostsBaselineFile) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// hostsMappings groups the addresses of each host name, sorted.
+func hostsMappings(entries []HostsEntry) map[string][]string {
+	m := map[string][]string{}
+	for _, e := range entries {
+		m[e.Hostname] = append(m[e.Hostname], e.IP)
+	}
+	for _, ips := range m {
+		sort.Strings(ips)
+	}
+	return m
+}
+
+// diffHosts returns the host names added, removed or mapped to different
+// addresses since the baseline, sorted by host name.
+func diffHosts(baseline, current []HostsEntry) []HostsChange {
+	old, now := hostsMappings(baseline), hostsMappings(current)
+	var changes []HostsChange
+	for name, ips := range now {
+		prev, ok := old[name]
+		switch {
+		case !ok:
+			changes = append(changes, HostsChange{Kind: hostsAdded, Hostname: name, New: ips})
+		case strings.Join(prev, " ") != strings.Join(ips, " "):
+			changes = append(changes, HostsChange{Kind: hostsChanged, Hostname: name, Old: prev, New: ips})
+		}
+	}
+	for name, ips := range old {
+		if _, ok := now[name]; !ok {
+			changes = append(changes, HostsChange{Kind: hostsRemoved, Hostname: name, Old: ips})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Hostname < changes[j].Hostname })
+	return changes
+}
+
+// formatHostsEntries renders the entries as a table.
+func formatHostsEntries(entries []HostsEntry) string {
+	if len(entries) == 0 {
+		return "The hosts file has no mappings."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tADDRESS\tHOST NAME")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", e.Line, e.IP, e.Hostname)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatHostsChanges renders the changes as a table.
+func formatHostsChanges(changes []HostsChange) string {
+	if len(changes) == 0 {
+		return "No changes."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tHOST NAME\tBASELINE\tNOW")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Kind, c.Hostname, dash(strings.Join(c.Old, ", ")), dash(strings.Join(c.New, ", ")))
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// analyzeHostsChanges warns about mappings added or changed since the
+// baseline. Removed mappings are listed but not reported.
+func analyzeHostsChanges(changes []HostsChange) []Finding {
+	var findings []Finding
+	for _, c := range changes {
+		switch c.Kind {
+		case hostsAdded:
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Hosts file maps %s to %s since the baseline.", c.Hostname, strings.Join(c.New, ", "))})
+		case hostsChanged:
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Hosts file changed %s from %s to %s since the baseline.", c.Hostname, strings.Join(c.Old, ", "), strings.Join(c.New, ", "))})
+		}
+	}
+	return findings
+}
+
+// analyzeSensitiveHosts returns the entries for sensitive domains (see
+// sensitiveDomain): a redirect is high risk (error), a block (a loopback
+// or unspecified address) a warning.
+func analyzeSensitiveHosts(entries []HostsEntry) ([]HostsEntry, []Finding) {
+	var sensitive []HostsEntry
+	var findings []Finding
+	for _, e := range entries {
+		if !sensitiveDomain(e.Hostname) {
+			continue
+		}
+		sensitive = append(sensitive, e)
+		if isBlockAddress(e.IP) {
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Hosts file line %d blocks %s (%s).", e.Line, e.Hostname, e.IP)})
+		} else {
+			findings = append(findings, Finding{Level: levelError,
+				Message: fmt.Sprintf("Hosts file line %d redirects %s to %s: high risk, a local server can impersonate it.", e.Line, e.Hostname, e.IP)})
+		}
+	}
+	return sensitive, findings
+}
+
+// sensitiveDomain reports whether name is an exam, education, security
+// vendor or major service domain.
+func sensitiveDomain(name string) bool {
+	for _, d := range sensitiveDomains {
+		if matchesDomain(name, d) {
+			return true
+		}
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostsFilePath returns the location of the system hosts file.
+func hostsFilePath() string {
+	if runtime.GOOS == "windows" {
+		root := os.Getenv("SystemRoot")
+		if root == "" {
+			root = `C:\Windows`
+		}
+		return filepath.Join(root, "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// parseHostsFile returns one entry per host name. Comments and lines
+// without a valid address are skipped.
+func parseHostsFile(data string) []HostsEntry {
+	var entries []HostsEntry
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || net.ParseIP(stripZone(fields[0])) == nil {
+			continue
+		}
+		for _, name := range fields[1:] {
+			entries = append(entries, HostsEntry{IP: fields[0], Hostname: strings.ToLower(name), Line: n})
+		}
+	}
+	return entries
+}
+
+// isBlockAddress reports whether ip is an address hosts files use to block
+// a name (unspecified or loopback) rather than redirect it.
+func isBlockAddress(ip string) bool {
+	parsed := net.ParseIP(stripZone(ip))
+	return parsed != nil && (parsed.IsUnspecified() || parsed.IsLoopback())
+}
+
+// matchesDomain reports whether name is domain or one of its subdomains.
+func matchesDomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHostsFile(t *testing.T) {
+	data := "# comment\n127.0.0.1\tlocalhost\n::1 localhost ip6-localhost # loopback\n\nnot-an-ip example.com\n10.0.0.5 Moodle.Example.edu\n"
+	want := []HostsEntry{
+		{IP: "127.0.0.1", Hostname: "localhost", Line: 2},
+		{IP: "::1", Hostname: "localhost", Line: 3},
+		{IP: "::1", Hostname: "ip6-localhost", Line: 3},
+		{IP: "10.0.0.5", Hostname: "moodle.example.edu", Line: 6},
+	}
+	if got := parseHostsFile(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseHostsFile = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnalyzeSensitiveHosts(t *testing.T) {
+	entries := []HostsEntry{
+		{IP: "127.0.0.1", Hostname: "localhost", Line: 1},
+		{IP: "10.0.0.5", Hostname: "moodle.example.edu", Line: 2},
+		{IP: "0.0.0.0", Hostname: "update.eset.com", Line: 3},
+		{IP: "203.0.113.7", Hostname: "www.google.com", Line: 4},
+		{IP: "203.0.113.7", Hostname: "notgoogle.com", Line: 5},
+	}
+	sensitive, findings := analyzeSensitiveHosts(entries)
+	if want := entries[1:4]; !reflect.DeepEqual(sensitive, want) {
+		t.Errorf("sensitive entries = %+v, want %+v", sensitive, want)
+	}
+	assertFindings(t, findings, []string{
+		"error: Hosts file line 2 redirects moodle.example.edu to 10.0.0.5",
+		"warn: Hosts file line 3 blocks update.eset.com (0.0.0.0).",
+		"error: Hosts file line 4 redirects www.google.com to 203.0.113.7",
+	})
+}
+
+func TestHostsBaseline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hosts")
+	original := readFixture(t, "hosts.txt")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries := parseHostsFile(original)
+	recorded := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	// The first run records the baseline, later runs return it
+	if _, created, err := loadHostsBaseline(dir, path, entries, recorded); err != nil || !created {
+		t.Fatalf("first loadHostsBaseline: created %v, %v; want true, nil", created, err)
+	}
+	redirected := append(entries, HostsEntry{IP: "203.0.113.7", Hostname: "proctorio.com", Line: 9})
+	baseline, created, err := loadHostsBaseline(dir, path, redirected, recorded.Add(time.Hour))
+	if err != nil || created || !baseline.Recorded.Equal(recorded) {
+		t.Fatalf("second loadHostsBaseline: recorded %s, created %v, %v; want %s, false, nil", baseline.Recorded, created, err, recorded)
+	}
+	if changes := diffHosts(baseline.Entries, redirected); len(changes) != 1 || changes[0].Hostname != "proctorio.com" {
+		t.Errorf("changes since the baseline = %+v, want proctorio.com added", changes)
+	}
+
+	// Re-baselining records the hosts file as it is now
+	if err := os.WriteFile(path, []byte(original+"203.0.113.7 proctorio.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rebased := recorded.Add(2 * time.Hour)
+	if err := resetHostsBaseline(dir, path, rebased); err != nil {
+		t.Fatalf("resetHostsBaseline: %v", err)
+	}
+	baseline, created, err = loadHostsBaseline(dir, path, nil, rebased.Add(time.Hour))
+	if err != nil || created || !baseline.Recorded.Equal(rebased) {
+		t.Fatalf("loadHostsBaseline after reset: recorded %s, created %v, %v; want %s, false, nil", baseline.Recorded, created, err, rebased)
+	}
+	if changes := diffHosts(baseline.Entries, redirected); len(changes) != 0 {
+		t.Errorf("changes since the new baseline: %+v", changes)
+	}
+}
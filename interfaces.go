@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// NetInterface is one network interface as reported by the "Cek IP" check.
+type NetInterface struct {
+	Name      string   `json:"name"`
+	Index     int      `json:"index"`
+	MAC       string   `json:"mac,omitempty"`
+	MTU       int      `json:"mtu"`
+	Flags     []string `json:"flags"`
+	IPv4      []string `json:"ipv4"` // CIDR notation
+	IPv6      []string `json:"ipv6"`
+	State     string   `json:"state,omitempty"`     // Operational state, where the OS reports it
+	Kind      string   `json:"kind,omitempty"`      // loopback, wireless, virtual or ethernet, where known
+	SpeedMbps int      `json:"speedMbps,omitempty"` // Link speed, where known
+	Gateway   string   `json:"gateway,omitempty"`   // IPv4 default gateway via this interface
+}
+
+// InterfaceInventory is the structured output of the "Cek IP" check.
+type InterfaceInventory struct {
+	Interfaces []NetInterface `json:"interfaces"`
+	DNS        []string       `json:"dns,omitempty"` // System resolvers, where available
+}
+
+// collectInterfaces lists the interfaces known to the OS, enriched with
+// per-OS details (see enrichInterfaces).
+func collectInterfaces() (InterfaceInventory, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return InterfaceInventory{}, err
+	}
+
+	var inv InterfaceInventory
+	for _, iface := range ifaces {
+		ni := NetInterface{
+			Name:  iface.Name,
+			Index: iface.Index,
+			MAC:   iface.HardwareAddr.String(),
+			MTU:   iface.MTU,
+			Flags: strings.Split(iface.Flags.String(), "|"),
+			IPv4:  []string{},
+			IPv6:  []string{},
+		}
+		if iface.Flags == 0 {
+			ni.Flags = []string{}
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			ni.Kind = "loopback"
+		}
+
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if ipnet.IP.To4() != nil {
+				ni.IPv4 = append(ni.IPv4, ipnet.String())
+			} else {
+				ni.IPv6 = append(ni.IPv6, ipnet.String())
+			}
+		}
+		inv.Interfaces = append(inv.Interfaces, ni)
+	}
+
+	enrichInterfaces(&inv)
+	sort.SliceStable(inv.Interfaces, func(i, j int) bool { return inv.Interfaces[i].Index < inv.Interfaces[j].Index })
+	return inv, nil
+}
+
+// checkInterfaces implements "Cek IP": it logs the inventory as a table and
+// attaches it to the run result for JSON exports.
+func checkInterfaces(r *checkRun) {
+	r.header("Status : Reading interfaces...")
+	inv, err := collectInterfaces()
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot list network interfaces: %v", err))
+		return
+	}
+	r.setData("interfaces", inv)
+	r.log(formatInterfaceTable(inv))
+}
+
+// formatInterfaceTable renders the inventory as an aligned text table, one
+// row per address.
+func formatInterfaceTable(inv InterfaceInventory) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INTERFACE\tSTATE\tMAC\tMTU\tADDRESS\tGATEWAY")
+	for _, ni := range inv.Interfaces {
+		state := ni.State
+		if state == "" {
+			state = "down"
+			if hasFlag(ni.Flags, "up") {
+				state = "up"
+			}
+		}
+		mac := ni.MAC
+		if mac == "" {
+			mac = "-"
+		}
+		addrs := append(append([]string{}, ni.IPv4...), ni.IPv6...)
+		if len(addrs) == 0 {
+			addrs = []string{"-"}
+		}
+		gateway := ni.Gateway
+		if gateway == "" {
+			gateway = "-"
+		}
+		for i, addr := range addrs {
+			if i == 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", ni.Name, state, mac, ni.MTU, addr, gateway)
+			} else {
+				fmt.Fprintf(w, "\t\t\t\t%s\t\n", addr)
+			}
+		}
+	}
+	w.Flush()
+
+	if len(inv.DNS) > 0 {
+		fmt.Fprintf(&b, "\nDNS servers : %s", strings.Join(inv.DNS, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// hasFlag reports whether flags contains name.
+func hasFlag(flags []string, name string) bool {
+	for _, f := range flags {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResolvConf returns the nameserver entries of a resolv.conf file.
+func parseResolvConf(data string) []string {
+	var servers []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers
+}
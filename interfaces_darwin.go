@@ -0,0 +1,11 @@
+package main
+
+import "os"
+
+// enrichInterfaces adds the resolvers from /etc/resolv.conf, which macOS
+// keeps in sync with its dynamic DNS configuration.
+func enrichInterfaces(inv *InterfaceInventory) {
+	if data, err := os.ReadFile("/etc/resolv.conf"); err == nil {
+		inv.DNS = parseResolvConf(string(data))
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// enrichInterfaces adds the operational state, link type and speed from
+// /sys/class/net, default gateways from /proc/net/route and the resolvers
+// from /etc/resolv.conf.
+func enrichInterfaces(inv *InterfaceInventory) {
+	var gateways map[string]string
+	if data, err := os.ReadFile("/proc/net/route"); err == nil {
+		gateways = defaultGateways(parseProcRoutes(string(data)))
+	}
+
+	for i := range inv.Interfaces {
+		ni := &inv.Interfaces[i]
+		dir := filepath.Join("/sys/class/net", ni.Name)
+
+		ni.State = readTrimmed(filepath.Join(dir, "operstate"))
+		if speed, err := strconv.Atoi(readTrimmed(filepath.Join(dir, "speed"))); err == nil && speed > 0 {
+			ni.SpeedMbps = speed
+		}
+		switch {
+		case ni.Kind != "":
+		case exists(filepath.Join(dir, "wireless")):
+			ni.Kind = "wireless"
+		case exists(filepath.Join("/sys/devices/virtual/net", ni.Name)):
+			ni.Kind = "virtual"
+		case readTrimmed(filepath.Join(dir, "type")) == "1": // ARPHRD_ETHER
+			ni.Kind = "ethernet"
+		}
+		ni.Gateway = gateways[ni.Name]
+	}
+
+	if data, err := os.ReadFile("/etc/resolv.conf"); err == nil {
+		inv.DNS = parseResolvConf(string(data))
+	}
+}
+
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
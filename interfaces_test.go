@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	want := []string{"192.168.1.1", "1.1.1.1", "fe80::1%wlp2s0"}
+	if got := parseResolvConf(readFixture(t, "resolv.conf")); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseResolvConf = %q, want %q", got, want)
+	}
+	if got := parseResolvConf(""); got != nil {
+		t.Errorf("parseResolvConf(\"\") = %q, want nil", got)
+	}
+}
+
+func TestFormatInterfaceTable(t *testing.T) {
+	inv := InterfaceInventory{
+		Interfaces: []NetInterface{
+			{Name: "lo", MTU: 65536, Flags: []string{"up", "loopback"}, IPv4: []string{"127.0.0.1/8"}, IPv6: []string{"::1/128"}},
+			{Name: "wlp2s0", MAC: "3c:22:fb:01:02:03", MTU: 1500, Flags: []string{"up", "broadcast"}, State: "up",
+				IPv4: []string{"192.168.1.23/24"}, IPv6: []string{"fe80::3e22:fbff:fe01:203/64"}, Gateway: "192.168.1.1"},
+			{Name: "enp0s31f6", MAC: "8c:16:45:aa:bb:cc", MTU: 1500, Flags: []string{"broadcast"}},
+			{Name: "wg0", MTU: 1420, Flags: []string{"up"}, State: "unknown", IPv4: []string{"10.8.0.2/32"}},
+		},
+		DNS: []string{"192.168.1.1", "1.1.1.1"},
+	}
+	// Continuation lines end in column padding, which the fixture omits
+	want := strings.TrimSuffix(readFixture(t, "interface_table.txt"), "\n")
+	if got := trimLines(formatInterfaceTable(inv)); got != want {
+		t.Errorf("formatInterfaceTable =\n%s\nwant\n%s", got, want)
+	}
+
+	// No interfaces and no resolvers: the header alone
+	if got := formatInterfaceTable(InterfaceInventory{}); got != "INTERFACE  STATE  MAC  MTU  ADDRESS  GATEWAY" {
+		t.Errorf("empty inventory = %q", got)
+	}
+}
+
+// trimLines removes trailing spaces from every line.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// Adapter types reported by GetAdaptersInfo.
+const (
+	adapterTypeEthernet = 6
+	adapterTypeLoopback = 24
+	adapterTypeWireless = 71
+)
+
+// enrichInterfaces adds the link type and default gateway of each adapter
+// from GetAdaptersInfo, matched by MAC address.
+func enrichInterfaces(inv *InterfaceInventory) {
+	size := uint32(16 * 1024)
+	buf := make([]byte, size)
+	err := syscall.GetAdaptersInfo((*syscall.IpAdapterInfo)(unsafe.Pointer(&buf[0])), &size)
+	if err == syscall.ERROR_BUFFER_OVERFLOW {
+		buf = make([]byte, size)
+		err = syscall.GetAdaptersInfo((*syscall.IpAdapterInfo)(unsafe.Pointer(&buf[0])), &size)
+	}
+	if err != nil {
+		return
+	}
+
+	for ai := (*syscall.IpAdapterInfo)(unsafe.Pointer(&buf[0])); ai != nil; ai = ai.Next {
+		mac := net.HardwareAddr(ai.Address[:ai.AddressLength]).String()
+		for i := range inv.Interfaces {
+			ni := &inv.Interfaces[i]
+			if mac == "" || ni.MAC != mac {
+				continue
+			}
+			switch ai.Type {
+			case adapterTypeEthernet:
+				ni.Kind = "ethernet"
+			case adapterTypeWireless:
+				ni.Kind = "wireless"
+			case adapterTypeLoopback:
+				ni.Kind = "loopback"
+			}
+			if gw := cString(ai.GatewayList.IpAddress.String[:]); gw != "" && gw != "0.0.0.0" {
+				ni.Gateway = gw
+			}
+		}
+	}
+}
+
+// cString converts a NUL-terminated byte array to a string.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
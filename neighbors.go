@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// neighborStateFileName is stored next to the executable (see
+// getAppBaseDir). It remembers the gateway MACs seen by earlier runs.
+const neighborStateFileName = "checkpoint-neighbors.json"
+
+// neighborStateMu serialises reads and writes of the neighbor state file.
+var neighborStateMu sync.Mutex
+
+// Neighbor is one entry of the ARP (IPv4) or neighbor (IPv6) table.
+type Neighbor struct {
+	Family    string `json:"family"`
+	IP        string `json:"ip"`
+	MAC       string `json:"mac,omitempty"` // Lower-case, colon separated; empty while unresolved
+	Interface string `json:"interface,omitempty"`
+	State     string `json:"state,omitempty"` // As reported by the OS, lower-case
+	Gateway   bool   `json:"gateway,omitempty"`
+}
+
+// NeighborTable is the structured output of the "ARP Table" check.
+type NeighborTable struct {
+	Neighbors []Neighbor `json:"neighbors"`
+	Findings  []Finding  `json:"findings"`
+}
+
+// gatewaySighting is the last MAC seen for a gateway IP.
+type gatewaySighting struct {
+	MAC       string    `json:"mac"`
+	Interface string    `json:"interface,omitempty"`
+	Seen      time.Time `json:"seen"`
+}
+
+// neighborState is the content of the neighbor state file.
+type neighborState struct {
+	Gateways map[string]gatewaySighting `json:"gateways"`
+}
+
+// checkNeighbors implements "ARP Table": it reads the neighbor table (see
+// readNeighbors), prints it and looks for ARP spoofing signals.
+func checkNeighbors(r *checkRun) {
+	r.header("Status : Reading neighbor table...")
+	neighbors, err := readNeighbors(r)
+	if r.stopped() {
+		return
+	}
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read neighbor table: %v", err))
+		return
+	}
+
+	gateways := map[string]bool{}
+	if routes, err := readRoutes(r); err == nil {
+		for _, gw := range defaultGateways(routes) {
+			gateways[gw] = true
+		}
+	} else if !r.stopped() {
+		r.log(fmt.Sprintf("[WARN] Cannot read the default gateway (%v); gateway checks skipped.", err))
+	}
+	for i := range neighbors {
+		neighbors[i].Gateway = gateways[neighbors[i].IP]
+	}
+	sortNeighbors(neighbors)
+
+	table := NeighborTable{Neighbors: neighbors, Findings: analyzeNeighbors(neighbors)}
+	if len(gateways) > 0 {
+		baseDir, err := r.app.getAppBaseDir()
+		var findings []Finding
+		if err == nil {
+			findings, err = compareGatewayMACs(baseDir, neighbors, time.Now())
+		}
+		if err != nil {
+			r.log(fmt.Sprintf("[WARN] Gateway history not updated: %v", err))
+		}
+		table.Findings = append(table.Findings, findings...)
+	}
+	if table.Neighbors == nil {
+		table.Neighbors = []Neighbor{}
+	}
+
+	r.setData("neighbors", table)
+	r.log(formatNeighborTable(neighbors))
+	if len(table.Findings) == 0 {
+		r.log("[OK] No MAC address answers for several IPs and the gateway MAC is unchanged.")
+	}
+	r.report(table.Findings)
+}
+
+// sortNeighbors orders neighbors by family, interface and address.
+func sortNeighbors(neighbors []Neighbor) {
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		a, b := neighbors[i], neighbors[j]
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		if a.Interface != b.Interface {
+			return a.Interface < b.Interface
+		}
+		ipA, ipB := net.ParseIP(a.IP), net.ParseIP(b.IP)
+		if ipA != nil && ipB != nil {
+			return string(ipA.To16()) < string(ipB.To16())
+		}
+		return a.IP < b.IP
+	})
+}
+
+// formatNeighborTable renders neighbors as an aligned text table.
+func formatNeighborTable(neighbors []Neighbor) string {
+	if len(neighbors) == 0 {
+		return "The neighbor table is empty."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tMAC\tINTERFACE\tSTATE\tNOTE")
+	for _, n := range neighbors {
+		mac, iface, state, note := n.MAC, n.Interface, n.State, ""
+		if mac == "" {
+			mac = "-"
+		}
+		if iface == "" {
+			iface = "-"
+		}
+		if state == "" {
+			state = "-"
+		}
+		if n.Gateway {
+			note = "default gateway"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", n.IP, mac, iface, state, note)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// analyzeNeighbors flags unicast MACs that answer for several IPv4
+// addresses. If one of them is the gateway this is the classic ARP spoofing
+// pattern and reported as an error.
+func analyzeNeighbors(neighbors []Neighbor) []Finding {
+	byMAC := map[string][]Neighbor{}
+	var macs []string
+	for _, n := range neighbors {
+		if n.Family != familyIPv4 || !unicastMAC(n.MAC) {
+			continue
+		}
+		if _, ok := byMAC[n.MAC]; !ok {
+			macs = append(macs, n.MAC)
+		}
+		byMAC[n.MAC] = append(byMAC[n.MAC], n)
+	}
+
+	var findings []Finding
+	for _, mac := range macs {
+		group := byMAC[mac]
+		if len(group) < 2 {
+			continue
+		}
+		var ips []string
+		level, gateway := levelWarn, ""
+		for _, n := range group {
+			ips = append(ips, n.IP)
+			if n.Gateway {
+				level, gateway = levelError, n.IP
+			}
+		}
+		msg := fmt.Sprintf("MAC %s answers for %d addresses: %s.", mac, len(group), strings.Join(ips, ", "))
+		if gateway != "" {
+			msg = fmt.Sprintf("MAC %s answers for the gateway %s and %d other address(es): %s. This is a typical sign of ARP spoofing.", mac, gateway, len(group)-1, strings.Join(ips, ", "))
+		}
+		findings = append(findings, Finding{Level: level, Message: msg})
+	}
+	return findings
+}
+
+// unicastMAC reports whether mac is a resolved unicast address.
+func unicastMAC(mac string) bool {
+	hw, err := net.ParseMAC(mac)
+	return err == nil && len(hw) == 6 && hw[0]&1 == 0 && mac != "00:00:00:00:00:00"
+}
+
+// compareGatewayMACs reports gateways whose MAC differs from the one seen
+// by the previous run and records the current MACs in the state file in
+// baseDir for the next one. A change is a warning: it is also what moving
+// to another network with the same gateway address looks like.
+func compareGatewayMACs(baseDir string, neighbors []Neighbor, now time.Time) ([]Finding, error) {
+	neighborStateMu.Lock()
+	defer neighborStateMu.Unlock()
+
+	path := filepath.Join(baseDir, neighborStateFileName)
+
+	state := neighborState{Gateways: map[string]gatewaySighting{}}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", neighborStateFileName, err)
+		}
+		if state.Gateways == nil {
+			state.Gateways = map[string]gatewaySighting{}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, n := range neighbors {
+		if !n.Gateway || !unicastMAC(n.MAC) {
+			continue
+		}
+		if prev, ok := state.Gateways[n.IP]; ok && prev.MAC != n.MAC {
+			findings = append(findings, Finding{Level: levelWarn, Message: fmt.Sprintf(
+				"Gateway %s changed MAC from %s (seen %s) to %s. Unless this machine moved to another network, the gateway may be spoofed.",
+				n.IP, prev.MAC, prev.Seen.Format("2006-01-02 15:04"), n.MAC)})
+		}
+		state.Gateways[n.IP] = gatewaySighting{MAC: n.MAC, Interface: n.Interface, Seen: now}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return findings, err
+	}
+	return findings, os.WriteFile(path, data, 0644)
+}
+
+// normalizeMAC returns mac in lower-case, colon-separated, zero-padded
+// form ("0:1b:2c:..." on macOS, "00-1B-2C-..." on Windows), or "" if it is
+// not a MAC address.
+func normalizeMAC(mac string) string {
+	parts := strings.FieldsFunc(mac, func(r rune) bool { return r == ':' || r == '-' })
+	if len(parts) != 6 {
+		return ""
+	}
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 16, 8)
+		if err != nil {
+			return ""
+		}
+		parts[i] = fmt.Sprintf("%02x", v)
+	}
+	return strings.Join(parts, ":")
+}
+
+// neighborFamily returns the family of an IP address.
+func neighborFamily(ip string) string {
+	if strings.Contains(ip, ":") {
+		return familyIPv6
+	}
+	return familyIPv4
+}
+
+// parseProcARP parses /proc/net/arp (IPv4 only).
+func parseProcARP(data string) []Neighbor {
+	var neighbors []Neighbor
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		// IP address  HW type  Flags  HW address  Mask  Device
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		flags, _ := strconv.ParseUint(strings.TrimPrefix(fields[2], "0x"), 16, 32)
+		n := Neighbor{Family: familyIPv4, IP: fields[0], Interface: fields[5], State: "incomplete"}
+		switch {
+		case flags&0x4 != 0: // ATF_PERM
+			n.State = "permanent"
+		case flags&0x2 != 0: // ATF_COM
+			n.State = "complete"
+		}
+		if n.State != "incomplete" {
+			n.MAC = normalizeMAC(fields[3])
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors
+}
+
+// parseIPNeigh parses `ip neigh show`, e.g.
+// "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff router REACHABLE".
+func parseIPNeigh(out string) []Neighbor {
+	var neighbors []Neighbor
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+		n := Neighbor{Family: neighborFamily(fields[0]), IP: fields[0]}
+		for i := 1; i < len(fields); i++ {
+			switch fields[i] {
+			case "dev":
+				if i+1 < len(fields) {
+					n.Interface = fields[i+1]
+					i++
+				}
+			case "lladdr":
+				if i+1 < len(fields) {
+					n.MAC = normalizeMAC(fields[i+1])
+					i++
+				}
+			}
+		}
+		if last := fields[len(fields)-1]; strings.ToUpper(last) == last && !strings.Contains(last, ":") {
+			n.State = strings.ToLower(last)
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors
+}
+
+// arpDarwinLine matches a macOS `arp -an` entry, e.g.
+// "? (192.168.1.1) at 0:11:22:33:44:55 on en0 ifscope [ethernet]".
+var arpDarwinLine = regexp.MustCompile(`\(([0-9.]+)\) at (\S+) on (\S+)(.*)$`)
+
+// parseArpDarwin parses `arp -an` on macOS.
+func parseArpDarwin(out string) []Neighbor {
+	var neighbors []Neighbor
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		m := arpDarwinLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		n := Neighbor{Family: familyIPv4, IP: m[1], MAC: normalizeMAC(m[2]), Interface: m[3], State: "dynamic"}
+		switch {
+		case n.MAC == "":
+			n.State = "incomplete"
+		case strings.Contains(m[4], "permanent"):
+			n.State = "permanent"
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors
+}
+
+// arpWindowsInterface matches the section header of Windows `arp -a`, e.g.
+// "Interface: 192.168.1.5 --- 0xb". The label is localised, the rest is not.
+var arpWindowsInterface = regexp.MustCompile(`^\S.*?([0-9.]+) --- 0x([0-9a-fA-F]+)`)
+
+// parseArpWindows parses `arp -a` on Windows. Entries carry the interface
+// index (decimal) of their section; readNeighbors maps it to a name.
+func parseArpWindows(out string) []Neighbor {
+	var neighbors []Neighbor
+	iface := ""
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := arpWindowsInterface.FindStringSubmatch(line); m != nil {
+			idx, _ := strconv.ParseUint(m[2], 16, 32)
+			iface = strconv.FormatUint(idx, 10)
+			continue
+		}
+		// Internet Address  Physical Address  Type
+		fields := strings.Fields(line)
+		if len(fields) != 3 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+		neighbors = append(neighbors, Neighbor{
+			Family:    familyIPv4,
+			IP:        fields[0],
+			MAC:       normalizeMAC(fields[1]),
+			Interface: iface,
+			State:     strings.ToLower(fields[2]),
+		})
+	}
+	return neighbors
+}
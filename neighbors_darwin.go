@@ -0,0 +1,10 @@
+package main
+
+import "strings"
+
+// readNeighbors parses the output of `arp -an` (numeric, so no slow
+// reverse lookups). A failing command is reported as a failed step.
+func readNeighbors(r *checkRun) ([]Neighbor, error) {
+	out := r.capture(Step{Command: "arp", Args: []string{"-an"}, Quiet: true})
+	return parseArpDarwin(strings.Join(out, "\n")), nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// readNeighbors parses `ip neigh show`, which covers IPv4 and IPv6 and
+// reports reachability, or /proc/net/arp where iproute2 is missing.
+func readNeighbors(r *checkRun) ([]Neighbor, error) {
+	if hasCommand("ip") {
+		out := r.capture(Step{Command: "ip", Args: []string{"neigh", "show"}, Quiet: true})
+		return parseIPNeigh(strings.Join(out, "\n")), nil
+	}
+	data, err := os.ReadFile("/proc/net/arp")
+	if err != nil {
+		return nil, err
+	}
+	return parseProcARP(string(data)), nil
+}
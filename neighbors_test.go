@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNeighborParsers(t *testing.T) {
+	v4 := func(ip, mac, iface, state string) Neighbor {
+		return Neighbor{Family: familyIPv4, IP: ip, MAC: mac, Interface: iface, State: state}
+	}
+	v6 := func(ip, mac, iface, state string) Neighbor {
+		return Neighbor{Family: familyIPv6, IP: ip, MAC: mac, Interface: iface, State: state}
+	}
+	tests := []struct {
+		fixture string
+		parse   func(string) []Neighbor
+		want    []Neighbor
+	}{
+		{"proc_net_arp.txt", parseProcARP, []Neighbor{
+			v4("192.168.1.1", "a4:91:b1:2c:3d:4e", "wlp2s0", "complete"),
+			v4("192.168.1.42", "3c:22:fb:10:20:30", "wlp2s0", "complete"),
+			v4("192.168.1.77", "", "wlp2s0", "incomplete"),
+			v4("10.0.10.5", "00:15:5d:01:02:03", "enp0s31f6", "permanent"),
+		}},
+		{"ip_neigh.txt", parseIPNeigh, []Neighbor{
+			v4("192.168.1.1", "a4:91:b1:2c:3d:4e", "wlp2s0", "reachable"),
+			v4("192.168.1.42", "3c:22:fb:10:20:30", "wlp2s0", "stale"),
+			v4("192.168.1.77", "", "wlp2s0", "incomplete"),
+			v4("192.168.1.90", "", "wlp2s0", "failed"),
+			v6("fe80::a691:b1ff:fe2c:3d4e", "a4:91:b1:2c:3d:4e", "wlp2s0", "reachable"),
+			v6("2001:db8:1::1", "a4:91:b1:2c:3d:4e", "wlp2s0", "stale"),
+		}},
+		{"arp_an_darwin.txt", parseArpDarwin, []Neighbor{
+			v4("192.168.1.1", "a4:91:b1:2c:3d:4e", "en0", "dynamic"),
+			v4("192.168.1.42", "3c:22:fb:10:20:30", "en0", "dynamic"),
+			v4("192.168.1.77", "", "en0", "incomplete"),
+			v4("192.168.1.23", "8c:85:90:0a:0b:0c", "en0", "permanent"), // Zero-padded
+			v4("224.0.0.251", "01:00:5e:00:00:fb", "en0", "permanent"),
+			v4("192.168.1.255", "ff:ff:ff:ff:ff:ff", "en0", "dynamic"),
+		}},
+		{"arp_a_windows.txt", parseArpWindows, []Neighbor{
+			v4("192.168.1.1", "a4:91:b1:2c:3d:4e", "12", "dynamic"),
+			v4("192.168.1.42", "3c:22:fb:10:20:30", "12", "dynamic"),
+			v4("192.168.1.255", "ff:ff:ff:ff:ff:ff", "12", "static"),
+			v4("224.0.0.22", "01:00:5e:00:00:16", "12", "static"),
+			v4("172.27.111.255", "ff:ff:ff:ff:ff:ff", "26", "static"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := tt.parse(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %d neighbors:\n%s\nwant %d:\n%s", len(got), formatNeighborTable(got), len(tt.want), formatNeighborTable(tt.want))
+			}
+		})
+	}
+}
+
+func TestAnalyzeNeighbors(t *testing.T) {
+	withGateway := func(neighbors []Neighbor, gateway string) []Neighbor {
+		for i := range neighbors {
+			neighbors[i].Gateway = neighbors[i].IP == gateway
+		}
+		return neighbors
+	}
+	spoofed := []Neighbor{
+		{Family: familyIPv4, IP: "192.168.1.1", MAC: "3c:22:fb:10:20:30", Gateway: true},
+		{Family: familyIPv4, IP: "192.168.1.42", MAC: "3c:22:fb:10:20:30"},
+	}
+	tests := []struct {
+		name      string
+		neighbors []Neighbor
+		want      []string
+	}{
+		// Broadcast, multicast and unresolved entries are never grouped
+		{"clean darwin table", withGateway(parseArpDarwin(readFixture(t, "arp_an_darwin.txt")), "192.168.1.1"), nil},
+		{"clean windows table", parseArpWindows(readFixture(t, "arp_a_windows.txt")), nil},
+		// The gateway's MAC also answering for its IPv6 addresses is normal
+		{"router with IPv6 addresses", parseIPNeigh(readFixture(t, "ip_neigh.txt")), nil},
+		{"shared MAC", []Neighbor{
+			{Family: familyIPv4, IP: "192.168.1.20", MAC: "3c:22:fb:10:20:30"},
+			{Family: familyIPv4, IP: "192.168.1.21", MAC: "3c:22:fb:10:20:30"},
+		}, []string{
+			"warn: MAC 3c:22:fb:10:20:30 answers for 2 addresses: 192.168.1.20, 192.168.1.21.",
+		}},
+		{"gateway spoofed", spoofed, []string{
+			"error: MAC 3c:22:fb:10:20:30 answers for the gateway 192.168.1.1 and 1 other address(es)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, analyzeNeighbors(tt.neighbors), tt.want)
+		})
+	}
+}
+
+func TestCompareGatewayMACs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	gateway := func(mac string) []Neighbor {
+		return []Neighbor{
+			{Family: familyIPv4, IP: "192.168.1.1", MAC: mac, Interface: "wlp2s0", Gateway: true},
+			{Family: familyIPv4, IP: "192.168.1.42", MAC: "3c:22:fb:10:20:30", Interface: "wlp2s0"},
+		}
+	}
+	steps := []struct {
+		mac  string
+		want []string
+	}{
+		{"a4:91:b1:2c:3d:4e", nil}, // First sighting
+		{"a4:91:b1:2c:3d:4e", nil},
+		{"", nil}, // Unresolved, keeps the last MAC
+		{"8c:85:90:0a:0b:0c", []string{"warn: Gateway 192.168.1.1 changed MAC from a4:91:b1:2c:3d:4e (seen 2026-10-01 09:00) to 8c:85:90:0a:0b:0c."}},
+	}
+	for i, step := range steps {
+		findings, err := compareGatewayMACs(dir, gateway(step.mac), now.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		assertFindings(t, findings, step.want)
+	}
+}
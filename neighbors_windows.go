@@ -0,0 +1,19 @@
+package main
+
+import "strings"
+
+// readNeighbors parses the output of `arp -a` and replaces the interface
+// indexes with adapter names. A failing command is reported as a failed
+// step.
+func readNeighbors(r *checkRun) ([]Neighbor, error) {
+	out := r.capture(Step{Command: "arp", Args: []string{"-a"}, Quiet: true})
+	neighbors := parseArpWindows(strings.Join(out, "\n"))
+
+	names := adapterNames()
+	for i := range neighbors {
+		if name, ok := names[neighbors[i].Interface]; ok {
+			neighbors[i].Interface = name
+		}
+	}
+	return neighbors, nil
+}
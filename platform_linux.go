@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// cleanupFiles is the Full Cleanup routine for Linux. It follows the XDG
+// base directory layout, so only the current user's data is touched. It
+// stops between sections once the run is cancelled or times out, and items
+// that cannot be removed fail the check.
+func cleanupFiles(r *checkRun) {
+	r.log("[Clean Files] Starting Linux cleanup...")
+	homeDir, _ := os.UserHomeDir()
+
+	// 1. Desktop, Documents, Downloads (Selective/Warning)
+	r.log("[Clean Files] Checking User Folders (Desktop, Documents, Downloads)...")
+	for _, name := range []string{"Desktop", "Documents", "Downloads"} {
+		files, _ := os.ReadDir(filepath.Join(homeDir, name))
+		r.log(fmt.Sprintf("  - %s: Contains %d files (Manual review recommended)", name, len(files)))
+	}
+
+	// 2. Empty Trash (files/ holds the items, info/ their .trashinfo records)
+	r.log("[Clean Files] Emptying Trash...")
+	trashPath := linuxTrashDir()
+	emptied := true
+	for _, sub := range []string{"files", "info", "expunged"} {
+		emptied = removeContents(r, filepath.Join(trashPath, sub)) && emptied
+	}
+	if r.stopped() {
+		return
+	}
+	if emptied {
+		r.log("  [OK] Trash emptied.")
+	}
+
+	// 3. Recent Items
+	r.log("[Clean Files] Clearing Recent Items...")
+	recent := filepath.Join(xdgDir("XDG_DATA_HOME", ".local/share"), "recently-used.xbel")
+	if err := os.Remove(recent); err != nil && !errors.Is(err, os.ErrNotExist) {
+		r.fail(fmt.Sprintf("Recent items not cleared: %v", err))
+	} else {
+		r.log("  [OK] Recent items cleared.")
+	}
+
+	// 4. Temp Files. /tmp is shared with other users and services, so only
+	// the per-user cache is cleared.
+	r.log("[Clean Files] Cleaning Temp Files...")
+	r.log("  [SKIP] System Temp (/tmp) is shared and cleared by systemd-tmpfiles. Skipping safe mode.")
+	cacheDir := xdgDir("XDG_CACHE_HOME", ".cache")
+	removeContents(r, filepath.Join(cacheDir, "thumbnails"))
+
+	// 5. Browser Cache
+	r.log("[Clean Files] Cleaning Browser Caches...")
+	for _, name := range []string{"google-chrome", "chromium", "microsoft-edge", "mozilla"} {
+		path := filepath.Join(cacheDir, name)
+		if _, err := os.Stat(path); err != nil || r.stopped() {
+			continue
+		}
+		r.log(fmt.Sprintf("  - Cleaning: %s", path))
+		removeContents(r, path)
+	}
+	if r.stopped() {
+		return
+	}
+
+	// 6. Removable Drives (udisks mounts them under /media/$USER or /run/media/$USER)
+	r.log("[Clean Files] Checking Removable Drives...")
+	user := os.Getenv("USER")
+	for _, root := range []string{filepath.Join("/media", user), filepath.Join("/run/media", user)} {
+		volumes, _ := os.ReadDir(root)
+		for _, vol := range volumes {
+			r.log(fmt.Sprintf("  [DETECTED] External Drive: %s - Please verify contents.", vol.Name()))
+			exec.Command("xdg-open", filepath.Join(root, vol.Name())).Start()
+		}
+	}
+
+	r.log("[Clean Files] Linux Cleanup Summary Complete.")
+}
+
+// screenshotTools are tried in order; desktops ship different ones.
+var screenshotTools = [][]string{
+	{"gnome-screenshot", "-f"},
+	{"spectacle", "-b", "-n", "-o"},
+	{"scrot", "-o"},
+	{"import", "-window", "root"}, // ImageMagick
+}
+
+// screenshotCommand captures the screen with the first available tool.
+func screenshotCommand(path string) (*exec.Cmd, error) {
+	for _, tool := range screenshotTools {
+		if _, err := exec.LookPath(tool[0]); err == nil {
+			args := append(append([]string{}, tool[1:]...), path)
+			return exec.Command(tool[0], args...), nil
+		}
+	}
+	return nil, errors.New("no screenshot tool found (install gnome-screenshot, spectacle, scrot or imagemagick)")
+}
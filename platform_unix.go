@@ -3,6 +3,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,90 +12,45 @@ import (
 )
 
 func getSysProcAttr() *syscall.SysProcAttr {
-	return nil
+	// Own process group so cancellation can kill the whole tree
+	return &syscall.SysProcAttr{Setpgid: true}
 }
 
-func cleanupMac(logFunc func(string)) {
-	logFunc("[Clean Files] Starting macOS cleanup...")
-
-	// 1. Desktop, Documents, Downloads (Selective/Warning)
-	logFunc("[Clean Files] Checking User Folders (Desktop, Documents, Downloads)...")
-	homeDir, _ := os.UserHomeDir()
-	targets := []string{
-		filepath.Join(homeDir, "Desktop"),
-		filepath.Join(homeDir, "Documents"),
-		filepath.Join(homeDir, "Downloads"),
-	}
-	for _, target := range targets {
-		files, _ := os.ReadDir(target)
-		logFunc(fmt.Sprintf("  - %s: Contains %d files (Manual review recommended)", filepath.Base(target), len(files)))
-	}
-
-	// 2. Empty Trash
-	logFunc("[Clean Files] Emptying Trash...")
-	// Using simple rm on ~/.Trash is effectively emptying it for the user
-	trashPath := filepath.Join(homeDir, ".Trash")
-	if err := os.RemoveAll(trashPath); err != nil {
-		logFunc(fmt.Sprintf("  [INFO] Trash empty or requires permission: %v", err))
-	} else {
-		logFunc("  [OK] Trash emptied.")
-		os.Mkdir(trashPath, 0700) // Recreate existence
+// killProcessTree kills the command and every process in its group.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
 	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
 
-	// 3. Clear Recent Items / Finder Quick Access
-	logFunc("[Clean Files] Clearing Recent Items...")
-	// This usually involves deleting LSSharedFileList plist, which is sensitive.
-	// Safer to just log advice or try clearing specific AppleScript.
-	logFunc("  [INFO] Clearing Recent Items requires Finder restart (Skipping to avoid interruption).")
+// setSocketTTL sets the IPv4 time-to-live of outgoing packets on a socket.
+func setSocketTTL(fd uintptr, ttl int) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TTL, ttl)
+}
 
-	// 4. Remove Autosave & Temp Files
-	logFunc("[Clean Files] Cleaning Temp Files...")
-	tempDirs := []string{
-		os.Getenv("TMPDIR"),
-		filepath.Join(homeDir, "Library/Caches"),
-		filepath.Join(homeDir, "Library/Saved Application State"),
-	}
-	for _, dir := range tempDirs {
-		if dir == "" {
-			continue
+// removeContents deletes everything inside dir but keeps dir itself. It
+// stops once the run is stopped, records items that could not be removed
+// as a failed step and reports whether everything was removed.
+func removeContents(r *checkRun, dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	} else if err != nil {
+		r.fail(fmt.Sprintf("%s could not be read: %v", dir, err))
+		return false
+	}
+	failed := 0
+	for _, e := range entries {
+		if r.stopped() {
+			return false
 		}
-		logFunc(fmt.Sprintf("  - Cleaning: %s", dir))
-		// We probably shouldn't wipe the ENTIRE Cache without care.
-		// But the checklist says "Remove autosave & temp files".
-		// Attempting to clear only current app temp would be safer, but the request seems broad.
-		// We will skip actual deletion of global caches to prevent system instability,
-		// unless it's strictly the /tmp equivalent.
-		if dir == os.Getenv("TMPDIR") {
-			logFunc("  [SKIP] System Temp cleanup on macOS is managed by OS. Skipping safe mode.")
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			failed++
 		}
 	}
-
-	// 5. Browser Cache
-	logFunc("[Clean Files] Cleaning Browser Caches...")
-	browserPaths := []string{
-		filepath.Join(homeDir, "Library/Caches/Google/Chrome/Default/Cache"),
-		filepath.Join(homeDir, "Library/Caches/com.apple.Safari"), // Restricted usually
-		filepath.Join(homeDir, "Library/Caches/Firefox/Profiles"),
+	if failed > 0 {
+		r.fail(fmt.Sprintf("%d item(s) in %s could not be removed (Partial/Locks)", failed, dir))
 	}
-	for _, bPath := range browserPaths {
-		if _, err := os.Stat(bPath); err == nil {
-			logFunc(fmt.Sprintf("  - Found cache: %s", filepath.Base(bPath)))
-			// os.RemoveAll(bPath) // Commented out for safety in this iteration unless confirmed.
-			logFunc("    [INFO] Please clear browser data via settings for complete privacy.")
-		}
-	}
-
-	// 6. Removable Drives
-	logFunc("[Clean Files] Checking Removable Drives...")
-	volumes, _ := os.ReadDir("/Volumes")
-	for _, vol := range volumes {
-		if vol.Name() != "Macintosh HD" && vol.Name() != "com.apple.TimeMachine.localsnapshots" {
-			logFunc(fmt.Sprintf("  [DETECTED] External Drive: %s - Please verify contents.", vol.Name()))
-			exec.Command("open", filepath.Join("/Volumes", vol.Name())).Start()
-		}
-	}
-
-	logFunc("[Clean Files] macOS Cleanup Summary Complete.")
+	return failed == 0
 }
-
-func cleanupWindows(logFunc func(string)) {}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"syscall"
 )
 
@@ -13,56 +14,114 @@ func getSysProcAttr() *syscall.SysProcAttr {
 	}
 }
 
-func cleanupWindows(logFunc func(string)) {
-	logFunc("[Clean Files] Starting Windows cleanup...")
+// killProcessTree kills the command and every child it spawned.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	kill.SysProcAttr = getSysProcAttr()
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// setSocketTTL sets the IPv4 time-to-live of outgoing packets on a socket.
+func setSocketTTL(fd uintptr, ttl int) error {
+	return syscall.SetsockoptInt(syscall.Handle(fd), syscall.IPPROTO_IP, syscall.IP_TTL, ttl)
+}
+
+// removeItemsScript deletes the items matched by path, a PowerShell
+// expression. Items that are locked or protected are skipped and reported
+// as [INFO]: PowerShell exits non-zero when the last command reported an
+// error, even a silenced one, and that would fail the whole cleanup.
+func removeItemsScript(path string) string {
+	return fmt.Sprintf(`$skipped = @()
+Get-ChildItem -Path %s -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue -ErrorVariable +skipped
+if ($skipped.Count -gt 0) {
+    Write-Output "[INFO] Skipped $($skipped.Count) item(s) that are in use or protected:"
+    $skipped | Select-Object -First 5 | ForEach-Object { Write-Output "[INFO]   $($_.TargetObject)" }
+}
+exit 0`, path)
+}
+
+// cleanupFiles is the Full Cleanup routine for Windows. Each PowerShell
+// command is a step of the run, so it is killed on cancel or timeout and a
+// failing command fails the check; files that cannot be deleted do not.
+func cleanupFiles(r *checkRun) {
+	r.log("[Clean Files] Starting Windows cleanup...")
 
 	// Helper to run PS command
 	runPS := func(desc, cmd string) {
-		logFunc(fmt.Sprintf("  - %s...", desc))
-		// We use -ErrorAction SilentlyContinue to avoid spamming errors for locked files
-		fullCmd := fmt.Sprintf("powershell -NoProfile -NonInteractive -Command \"%s\"", cmd)
-		c := exec.Command("cmd", "/C", fullCmd)
-		c.SysProcAttr = getSysProcAttr()
-		out, err := c.CombinedOutput()
-		if err != nil {
-			logFunc(fmt.Sprintf("    [INFO] %s (Partial/Locks)", desc))
-		} else if len(out) > 0 {
-			logFunc(fmt.Sprintf("    [OK] %s", string(out)))
-		} else {
-			logFunc("    [OK] Completed.")
+		if r.stopped() {
+			return
 		}
+		r.log(fmt.Sprintf("  - %s...", desc))
+		r.powerShell(cmd)
 	}
 
-	// 1. Recycle Bin
-	runPS("Emptying Recycle Bin", "Clear-RecycleBin -Force -ErrorAction SilentlyContinue")
+	// 1. Recycle Bin (fails on an empty bin, which needs no report)
+	runPS("Emptying Recycle Bin", "Clear-RecycleBin -Force -ErrorAction SilentlyContinue; exit 0")
 
 	// 2. Temp Folders (%temp%, temp)
-	runPS("Cleaning User Temp (%TEMP%)", "Get-ChildItem -Path $env:TEMP -Recurse -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue")
-	runPS("Cleaning System Temp (C:\\Windows\\Temp)", "Get-ChildItem -Path 'C:\\Windows\\Temp' -Recurse -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue")
+	runPS("Cleaning User Temp (%TEMP%)", removeItemsScript("$env:TEMP"))
+	runPS("Cleaning System Temp (C:\\Windows\\Temp)", removeItemsScript("'C:\\Windows\\Temp'"))
 
 	// 3. Browser Cache (Chrome, Edge, Firefox)
 	// Chrome
-	runPS("Cleaning Chrome Cache", "Get-ChildItem -Path \"$env:LOCALAPPDATA\\Google\\Chrome\\User Data\\Default\\Cache\\*\" -Recurse -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue")
+	runPS("Cleaning Chrome Cache", removeItemsScript("\"$env:LOCALAPPDATA\\Google\\Chrome\\User Data\\Default\\Cache\\*\""))
 	// Edge
-	runPS("Cleaning Edge Cache", "Get-ChildItem -Path \"$env:LOCALAPPDATA\\Microsoft\\Edge\\User Data\\Default\\Cache\\*\" -Recurse -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue")
+	runPS("Cleaning Edge Cache", removeItemsScript("\"$env:LOCALAPPDATA\\Microsoft\\Edge\\User Data\\Default\\Cache\\*\""))
 
 	// 4. Autosave / Recent
-	runPS("Cleaning Recent Items", "Get-ChildItem -Path \"$env:APPDATA\\Microsoft\\Windows\\Recent\\*\" -Recurse -Force -ErrorAction SilentlyContinue | Remove-Item -Recurse -Force -ErrorAction SilentlyContinue")
+	runPS("Cleaning Recent Items", removeItemsScript("\"$env:APPDATA\\Microsoft\\Windows\\Recent\\*\""))
+
+	if r.stopped() {
+		return
+	}
 
 	// 5. Thumbnails (Explorer) - Requires stopping explorer usually, skipping to avoid UI crash.
-	logFunc("  - Cleaning Thumbnails (Skipping to prevent explorer restart)")
+	r.log("  - Cleaning Thumbnails (Skipping to prevent explorer restart)")
 
 	// 6. Exam/Documents Check
-	logFunc("[Clean Files] Checking Documents/Desktop for 'exam' files...")
+	r.log("[Clean Files] Checking Documents/Desktop for 'exam' files...")
 	// Just looking for keyword 'exam' or 'ujian' to be safe/helpful
 	runPS("Scanning Desktop", "Get-ChildItem -Path $env:USERPROFILE\\Desktop -Filter *exam* -Recurse -ErrorAction SilentlyContinue | Select-Object Name")
 	runPS("Scanning Documents", "Get-ChildItem -Path $env:USERPROFILE\\Documents -Filter *exam* -Recurse -ErrorAction SilentlyContinue | Select-Object Name")
 
+	if r.stopped() {
+		return
+	}
+
 	// 7. Removable Drives
-	logFunc("[Clean Files] Checking Removable Drives...")
+	r.log("[Clean Files] Checking Removable Drives...")
 	runPS("Listing Removable Drives", "Get-CimInstance -ClassName Win32_LogicalDisk -Filter \"DriveType = 2\" | Select-Object DeviceID, VolumeName")
 
-	logFunc("[Clean Files] Windows Cleanup Summary Complete.")
+	if !r.stopped() {
+		r.log("[Clean Files] Windows Cleanup Summary Complete.")
+	}
 }
 
-func cleanupMac(logFunc func(string)) {}
+// screenshotCommand captures the primary screen with a PowerShell snippet.
+// NOTE: This requires .NET (System.Windows.Forms).
+func screenshotCommand(path string) (*exec.Cmd, error) {
+	psScript := fmt.Sprintf(`
+Add-Type -AssemblyName System.Windows.Forms
+Add-Type -AssemblyName System.Drawing
+$Screen = [System.Windows.Forms.Screen]::PrimaryScreen
+$Width = $Screen.Bounds.Width
+$Height = $Screen.Bounds.Height
+$Left = $Screen.Bounds.Left
+$Top = $Screen.Bounds.Top
+$Bitmap = New-Object System.Drawing.Bitmap $Width, $Height
+$Graphic = [System.Drawing.Graphics]::FromImage($Bitmap)
+$Graphic.CopyFromScreen($Left, $Top, 0, 0, $Bitmap.Size)
+$Bitmap.Save('%s')
+$Graphic.Dispose()
+$Bitmap.Dispose()
+`, path)
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", psScript)
+	cmd.SysProcAttr = getSysProcAttr()
+	return cmd, nil
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+// Port scan defaults, used when the settings leave a field unset.
+const (
+	defaultScanTarget  = "127.0.0.1"
+	defaultScanPorts   = "21,22,23,25,80,135,139,443,445,1433,3306,3389,5900,8080"
+	defaultScanTimeout = 500 * time.Millisecond
+	defaultScanWorkers = 64
+	bannerMaxBytes     = 256
+)
+
+// Port states reported by the scanner.
+const (
+	portOpen         = "open"
+	portClosed       = "closed"
+	portFiltered     = "filtered"      // TCP: no answer within the timeout
+	portOpenFiltered = "open|filtered" // UDP: no answer, which is also what an open port without a reply looks like
+)
+
+// PortScanSettings configures the "Check active network service ports"
+// check. Zero values select the defaults.
+type PortScanSettings struct {
+	Target    string `json:"target,omitempty"`    // IP, host name or local interface name
+	Ports     string `json:"ports,omitempty"`     // TCP ports, e.g. "22,80,8000-8100"
+	UDPPorts  string `json:"udpPorts,omitempty"`  // UDP ports to probe; none by default
+	TimeoutMs int    `json:"timeoutMs,omitempty"` // Per-port wait, 50-10000
+	Workers   int    `json:"workers,omitempty"`   // Parallel probes, 1-512
+}
+
+// validate checks the settings ranges.
+func (s PortScanSettings) validate() error {
+	if s.Target != "" && !hostnamePattern.MatchString(s.Target) {
+		return fmt.Errorf("invalid target %q", s.Target)
+	}
+	if _, err := parsePortList(s.Ports); err != nil {
+		return fmt.Errorf("ports: %w", err)
+	}
+	if _, err := parsePortList(s.UDPPorts); err != nil {
+		return fmt.Errorf("udpPorts: %w", err)
+	}
+	if s.TimeoutMs != 0 && (s.TimeoutMs < 50 || s.TimeoutMs > 10000) {
+		return errors.New("timeoutMs must be between 50 and 10000")
+	}
+	if s.Workers < 0 || s.Workers > 512 {
+		return errors.New("workers must be between 1 and 512")
+	}
+	return nil
+}
+
+// withDefaults fills unset fields.
+func (s PortScanSettings) withDefaults() PortScanSettings {
+	if s.Target == "" {
+		s.Target = defaultScanTarget
+	}
+	if s.Ports == "" {
+		s.Ports = defaultScanPorts
+	}
+	if s.TimeoutMs == 0 {
+		s.TimeoutMs = int(defaultScanTimeout / time.Millisecond)
+	}
+	if s.Workers == 0 {
+		s.Workers = defaultScanWorkers
+	}
+	return s
+}
+
+// parsePortList parses a comma-separated list of ports and ranges
+// ("22,80,8000-8100") into sorted, unique port numbers.
+func parsePortList(spec string) ([]int, error) {
+	seen := map[int]bool{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(item, "-")
+		first, err := parsePort(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parsePort(hi); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid range %q", item)
+			}
+		}
+		for p := first; p <= last; p++ {
+			seen[p] = true
+		}
+	}
+
+	ports := make([]int, 0, len(seen))
+	for p := range seen {
+		ports = append(ports, p)
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// parsePort parses a single port number.
+func parsePort(s string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || p < 1 || p > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return p, nil
+}
+
+// PortResult is the outcome of probing one port.
+type PortResult struct {
+	Proto     string  `json:"proto"` // tcp or udp
+	Port      int     `json:"port"`
+	State     string  `json:"state"`
+	Service   string  `json:"service,omitempty"` // From the service knowledge base
+	Risk      string  `json:"risk,omitempty"`
+	Banner    string  `json:"banner,omitempty"`
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+}
+
+// PortScan is the structured output of the port scan. Closed ports are
+// only counted, so full-range scans stay small.
+type PortScan struct {
+	Target   string       `json:"target"`
+	Address  string       `json:"address"`
+	Results  []PortResult `json:"results"` // Every port that is not closed, sorted by port
+	Closed   int          `json:"closed"`
+	Findings []Finding    `json:"findings"` // Open ports running medium or high risk services
+}
+
+// checkPortScan implements "Check active network service ports": it
+// probes the configured ports with a bounded pool of workers and reports
+// the open ones with any banner they sent, annotated from the service
+// knowledge base.
+func checkPortScan(r *checkRun) {
+	cfg := r.app.GetSettings().PortScan.withDefaults()
+	tcpPorts, _ := parsePortList(cfg.Ports)
+	udpPorts, _ := parsePortList(cfg.UDPPorts)
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+
+	r.header("Status : Scanning ports...",
+		fmt.Sprintf("Target : %s", cfg.Target),
+		fmt.Sprintf("Ports  : %d TCP, %d UDP", len(tcpPorts), len(udpPorts)))
+
+	ip, err := scanAddress(r.ctx, cfg.Target)
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot resolve %s: %v", cfg.Target, err))
+		return
+	}
+
+	results := scanPorts(r.ctx, ip, tcpPorts, udpPorts, timeout, cfg.Workers)
+	if r.stopped() {
+		return
+	}
+
+	kb := r.app.knownServices()
+	scan := PortScan{Target: cfg.Target, Address: ip.String(), Results: []PortResult{}, Findings: []Finding{}}
+	for _, res := range results {
+		if res.State == portClosed {
+			scan.Closed++
+			continue
+		}
+		if svc, ok := kb.lookup(res.Proto, res.Port); ok {
+			res.Service, res.Risk = svc.Name, svc.Risk
+			if f, ok := svc.finding(fmt.Sprintf("Port %d/%s is open", res.Port, res.Proto)); ok && res.State == portOpen {
+				scan.Findings = append(scan.Findings, f)
+			}
+		}
+		scan.Results = append(scan.Results, res)
+	}
+	r.setData("portScan", scan)
+
+	if len(scan.Results) > 0 {
+		r.log(formatPortTable(scan.Results))
+	}
+	open := 0
+	for _, res := range scan.Results {
+		if res.State == portOpen {
+			open++
+		}
+	}
+	if open == 0 {
+		r.log(fmt.Sprintf("[INFO] No active target services found (%d closed, %d without answer).", scan.Closed, len(scan.Results)))
+	} else {
+		r.log(fmt.Sprintf("[INFO] %d open, %d closed, %d without answer.", open, scan.Closed, len(scan.Results)-open))
+	}
+	r.report(scan.Findings)
+}
+
+// scanAddress resolves the scan target. Interface names select the
+// interface's first IPv4 address.
+func scanAddress(ctx context.Context, target string) (net.IP, error) {
+	if iface, err := net.InterfaceByName(target); err == nil {
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+				return ipnet.IP, nil
+			}
+		}
+		return nil, fmt.Errorf("interface %s has no IPv4 address", target)
+	}
+	return resolveTarget(ctx, target)
+}
+
+// scanPorts probes every port with at most workers probes in flight and
+// returns the results sorted by port, TCP before UDP.
+func scanPorts(ctx context.Context, ip net.IP, tcpPorts, udpPorts []int, timeout time.Duration, workers int) []PortResult {
+	type job struct {
+		proto string
+		port  int
+	}
+	jobs := make(chan job)
+	var mu sync.Mutex
+	var results []PortResult
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				addr := net.JoinHostPort(ip.String(), strconv.Itoa(j.port))
+				var res PortResult
+				if j.proto == "udp" {
+					res = scanUDP(ctx, addr, timeout)
+				} else {
+					res = scanTCP(ctx, addr, timeout)
+				}
+				res.Proto, res.Port = j.proto, j.port
+				mu.Lock()
+				results = append(results, res)
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, list := range []struct {
+		proto string
+		ports []int
+	}{{"tcp", tcpPorts}, {"udp", udpPorts}} {
+		for _, port := range list.ports {
+			select {
+			case jobs <- job{list.proto, port}:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Port != results[j].Port {
+			return results[i].Port < results[j].Port
+		}
+		return results[i].Proto < results[j].Proto
+	})
+	return results
+}
+
+// scanTCP connects to addr and, if the port is open, reads whatever the
+// service sends first (SSH, FTP, SMTP, ... greet the client).
+func scanTCP(ctx context.Context, addr string, timeout time.Duration) PortResult {
+	start := time.Now()
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return PortResult{State: portFiltered}
+		}
+		return PortResult{State: portClosed}
+	}
+	defer conn.Close()
+	res := PortResult{State: portOpen, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+
+	buf := make([]byte, bannerMaxBytes)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	if n, _ := conn.Read(buf); n > 0 {
+		res.Banner = cleanBanner(buf[:n])
+	}
+	return res
+}
+
+// udpProbes holds payloads that make common UDP services answer. Other
+// ports get an empty datagram.
+var udpProbes = map[int][]byte{
+	// DNS: standard query for the root NS records
+	53: {0x12, 0x34, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+	// NTP: version 3 client request
+	123: append([]byte{0x1b}, make([]byte, 47)...),
+}
+
+// scanUDP sends a datagram to addr. A reply means open and an ICMP port
+// unreachable (seen as a refused read) means closed; silence is ambiguous.
+func scanUDP(ctx context.Context, addr string, timeout time.Duration) PortResult {
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "udp", addr)
+	if err != nil {
+		return PortResult{State: portClosed}
+	}
+	defer conn.Close()
+
+	_, port, _ := net.SplitHostPort(addr)
+	p, _ := strconv.Atoi(port)
+	start := time.Now()
+	if _, err := conn.Write(udpProbes[p]); err != nil {
+		return PortResult{State: portClosed}
+	}
+	conn.SetReadDeadline(start.Add(timeout))
+	buf := make([]byte, bannerMaxBytes)
+	n, err := conn.Read(buf)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return PortResult{State: portOpenFiltered}
+		}
+		return PortResult{State: portClosed}
+	}
+	return PortResult{State: portOpen, LatencyMs: float64(time.Since(start).Microseconds()) / 1000, Banner: cleanBanner(buf[:n])}
+}
+
+// cleanBanner keeps the first line of a banner and drops anything not
+// printable, so binary replies do not garble the log.
+func cleanBanner(data []byte) string {
+	line, _, _ := strings.Cut(string(data), "\n")
+	line = strings.Map(func(r rune) rune {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, line)
+	return strings.TrimSpace(line)
+}
+
+// formatPortTable renders scan results as an aligned text table.
+func formatPortTable(results []PortResult) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PORT\tSTATE\tSERVICE\tRISK\tBANNER")
+	for _, res := range results {
+		service, risk, banner := res.Service, res.Risk, res.Banner
+		if service == "" {
+			service = "-"
+		}
+		if risk == "" {
+			risk = "-"
+		}
+		if banner == "" {
+			banner = "-"
+		}
+		fmt.Fprintf(w, "%d/%s\t%s\t%s\t%s\t%s\n", res.Port, res.Proto, strings.ToUpper(res.State), service, risk, banner)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePortList(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr string
+	}{
+		{"22", []int{22}, ""},
+		{"443, 22,80", []int{22, 80, 443}, ""},
+		{"8000-8003,8001,22,22", []int{22, 8000, 8001, 8002, 8003}, ""},
+		{"1,65535", []int{1, 65535}, ""},
+		{"21,,23,", []int{21, 23}, ""},
+		{"", []int{}, ""},
+		{"0", nil, `invalid port "0"`},
+		{"65536", nil, `invalid port "65536"`},
+		{"22,http", nil, `invalid port "http"`},
+		{"100-65536", nil, `invalid port "65536"`},
+		{"-22", nil, `invalid port ""`},
+		{"90-80", nil, `invalid range "90-80"`},
+	}
+	for _, tt := range tests {
+		got, err := parsePortList(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parsePortList(%q) error = %v, want %s", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePortList(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+// freePort returns a loopback port that was just released, so nothing
+// listens on it.
+func freePort(t *testing.T, network string) int {
+	t.Helper()
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.LocalAddr().(*net.UDPAddr).Port
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestScanPorts(t *testing.T) {
+	// A TCP service greeting the client, as SSH does
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n\x00\x01"))
+			conn.Close()
+		}
+	}()
+
+	// A UDP service answering every datagram
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			_, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			udp.WriteTo([]byte("pong\n"), addr)
+		}
+	}()
+
+	openTCP, closedTCP := ln.Addr().(*net.TCPAddr).Port, freePort(t, "tcp")
+	openUDP, closedUDP := udp.LocalAddr().(*net.UDPAddr).Port, freePort(t, "udp")
+	results := scanPorts(context.Background(), net.IPv4(127, 0, 0, 1), []int{openTCP, closedTCP}, []int{openUDP, closedUDP}, time.Second, 4)
+
+	type key struct {
+		proto string
+		port  int
+	}
+	got := map[key]PortResult{}
+	for i, res := range results {
+		got[key{res.Proto, res.Port}] = res
+		if i > 0 && (results[i-1].Port > res.Port || results[i-1].Port == res.Port && results[i-1].Proto > res.Proto) {
+			t.Errorf("results not sorted by port and protocol:\n%s", formatPortTable(results))
+		}
+	}
+	want := []struct {
+		key    key
+		state  string
+		banner string
+	}{
+		{key{"tcp", openTCP}, portOpen, "SSH-2.0-OpenSSH_9.6"},
+		{key{"tcp", closedTCP}, portClosed, ""},
+		{key{"udp", openUDP}, portOpen, "pong"},
+		{key{"udp", closedUDP}, portClosed, ""},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d:\n%s", len(results), len(want), formatPortTable(results))
+	}
+	for _, w := range want {
+		res := got[w.key]
+		if res.State != w.state || res.Banner != w.banner {
+			t.Errorf("%d/%s: got %s %q, want %s %q", w.key.port, w.key.proto, res.State, res.Banner, w.state, w.banner)
+		}
+	}
+}
+
+func TestCleanBanner(t *testing.T) {
+	if got := cleanBanner([]byte("220 ftp.school.example FTP ready\x07\r\nsecond line")); got != "220 ftp.school.example FTP ready" {
+		t.Errorf("cleanBanner = %q", got)
+	}
+	if got := cleanBanner([]byte{0xff, 0xfe, 0x00}); strings.TrimSpace(got) != "" {
+		t.Errorf("cleanBanner of binary data = %q, want empty", got)
+	}
+}
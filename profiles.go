@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// profilesFileName is stored next to the executable (see getAppBaseDir).
+const profilesFileName = "checkpoint-profiles.json"
+
+// Profile is a named, ordered list of checks run together.
+type Profile struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Checks      []string `json:"checks"` // Check IDs, in run order
+}
+
+// profilesFile is the layout of checkpoint-profiles.json.
+type profilesFile struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// builtinProfiles are always available; the profiles file can add to them
+// or replace one by reusing its ID.
+var builtinProfiles = []Profile{
+	{
+		ID:          "exam-room",
+		Name:        "Exam room readiness",
+		Description: "Checks a candidate machine before an exam session.",
+		Checks: []string{
+			"cek-ip", "ping-connectivity", "dns-config", "hosts-file", "proxy-config", "wifi-profiles",
+			"service-ports", "listening-ports", "browser-extensions",
+			"bluetooth-devices", "security-status", "firewall-status", "protection-health",
+			"startup-services", "registry-check",
+		},
+	},
+	{
+		ID:          "network-triage",
+		Name:        "Network triage",
+		Description: "Collects addressing, routing and connection state.",
+		Checks:      []string{"cek-ip", "cek-routing", "arp-table", "netstat", "ping-connectivity", "dns-config", "proxy-config", "service-ports"},
+	},
+	{
+		ID:          "full-audit",
+		Name:        "Full audit",
+		Description: "Runs every read-only check.",
+		Checks: []string{
+			"cek-ip", "cek-routing", "traceroute", "netstat", "listening-ports", "established-connections", "arp-table",
+			"ping-connectivity", "dns-config", "hosts-file", "proxy-config", "wifi-profiles",
+			"installed-applications", "ps-drives", "hklm-registry", "startup-services", "registry-check",
+			"security-status", "firewall-status", "protection-health",
+			"service-ports", "tls-certificates", "browser-extensions", "bluetooth-devices",
+		},
+	},
+}
+
+// loadProfiles merges the built-in profiles with the profiles file.
+// Invalid entries are skipped and reported in the returned warnings.
+func (a *App) loadProfiles() (warnings []string) {
+	a.profiles = append([]Profile{}, builtinProfiles...)
+
+	baseDir, err := a.getAppBaseDir()
+	if err != nil {
+		return []string{fmt.Sprintf("[WARN] Profiles file not loaded: %s", err)}
+	}
+	data, err := os.ReadFile(filepath.Join(baseDir, profilesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("[WARN] Profiles file not loaded: %s", err)}
+	}
+
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return []string{fmt.Sprintf("[WARN] Invalid %s: %s", profilesFileName, err)}
+	}
+
+	for _, p := range file.Profiles {
+		if err := validateProfile(p); err != nil {
+			warnings = append(warnings, fmt.Sprintf("[WARN] %s: skipping profile %q: %s", profilesFileName, p.ID, err))
+			continue
+		}
+		if i := a.profileIndex(p.ID); i >= 0 {
+			a.profiles[i] = p
+		} else {
+			a.profiles = append(a.profiles, p)
+		}
+	}
+	return warnings
+}
+
+// validateProfile checks that a profile is named and only lists known checks.
+func validateProfile(p Profile) error {
+	if p.ID == "" || p.Name == "" {
+		return errors.New("id and name are required")
+	}
+	if len(p.Checks) == 0 {
+		return errors.New("no checks listed")
+	}
+	var unknown []string
+	for _, id := range p.Checks {
+		if lookupCheck(id) == nil {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown checks: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// profileIndex returns the index of the profile with the given ID, or -1.
+func (a *App) profileIndex(id string) int {
+	for i, p := range a.profiles {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetProfiles returns the built-in and configured profiles
+func (a *App) GetProfiles() []Profile {
+	return append([]Profile{}, a.profiles...)
+}
+
+// ProfileProgressEvent is the payload of the "profile:progress" event,
+// emitted as each check of a profile run starts.
+type ProfileProgressEvent struct {
+	ProfileRunID string `json:"profileRunId"`
+	ProfileID    string `json:"profileId"`
+	Index        int    `json:"index"` // 1-based
+	Total        int    `json:"total"`
+	CheckID      string `json:"checkId"`
+	RunID        string `json:"runId"`
+}
+
+// ProfileSummary is the payload of the "profile:done" event.
+type ProfileSummary struct {
+	ProfileRunID string         `json:"profileRunId"`
+	ProfileID    string         `json:"profileId"`
+	ProfileName  string         `json:"profileName"`
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	DurationMs   int64          `json:"durationMs"`
+	Cancelled    bool           `json:"cancelled"`
+	Counts       map[string]int `json:"counts"` // Runs per status
+	Results      []RunResult    `json:"results"`
+}
+
+// profileRuns tracks in-flight profile runs so they can be cancelled.
+type profileRuns struct {
+	mu     sync.Mutex
+	nextID int
+	active map[string]context.CancelFunc
+}
+
+// RunProfile runs every check of a profile in order through the run queue
+// and returns a profile run ID that can be passed to CancelRun.
+func (a *App) RunProfile(profileID string) (string, error) {
+	i := a.profileIndex(profileID)
+	if i < 0 {
+		return "", fmt.Errorf("unknown profile: %s", profileID)
+	}
+	profile := a.profiles[i]
+
+	a.profileRuns.mu.Lock()
+	a.profileRuns.nextID++
+	id := fmt.Sprintf("profile-%d", a.profileRuns.nextID)
+	ctx, cancel := context.WithCancel(a.ctx)
+	if a.profileRuns.active == nil {
+		a.profileRuns.active = make(map[string]context.CancelFunc)
+	}
+	a.profileRuns.active[id] = cancel
+	a.profileRuns.mu.Unlock()
+
+	go a.runProfile(ctx, id, profile)
+	return id, nil
+}
+
+// cancelProfile stops a profile run. It reports whether the ID was a
+// running profile.
+func (a *App) cancelProfile(id string) bool {
+	a.profileRuns.mu.Lock()
+	cancel, ok := a.profileRuns.active[id]
+	a.profileRuns.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// runProfile runs the profile's checks one after another and emits a
+// combined summary at the end.
+func (a *App) runProfile(ctx context.Context, id string, profile Profile) {
+	summary := ProfileSummary{
+		ProfileRunID: id,
+		ProfileID:    profile.ID,
+		ProfileName:  profile.Name,
+		Start:        time.Now(),
+		Counts:       make(map[string]int),
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "log", fmt.Sprintf("=====================================\n[ Profile: %s ]\nTime : %s\nChecks : %d\n=====================================",
+		profile.Name, summary.Start.Format("2006-01-02 15:04:05"), len(profile.Checks)))
+
+	for i, checkID := range profile.Checks {
+		check := lookupCheck(checkID)
+		if check == nil {
+			continue // Validated on load; only possible for built-ins on a trimmed build
+		}
+
+		r := a.submit(check)
+		wailsRuntime.EventsEmit(a.ctx, "profile:progress", ProfileProgressEvent{
+			ProfileRunID: id,
+			ProfileID:    profile.ID,
+			Index:        i + 1,
+			Total:        len(profile.Checks),
+			CheckID:      check.ID,
+			RunID:        r.id,
+		})
+
+		select {
+		case <-r.finished:
+		case <-ctx.Done():
+			r.cancel(errRunCancelled)
+			if a.runs.dequeue(r) {
+				r.finish(statusCancelled)
+			}
+			<-r.finished
+		}
+
+		summary.Results = append(summary.Results, r.res)
+		summary.Counts[r.res.Status]++
+
+		if ctx.Err() != nil {
+			summary.Cancelled = true
+			break
+		}
+	}
+
+	summary.End = time.Now()
+	summary.DurationMs = summary.End.Sub(summary.Start).Milliseconds()
+
+	a.profileRuns.mu.Lock()
+	if cancel, ok := a.profileRuns.active[id]; ok {
+		cancel()
+		delete(a.profileRuns.active, id)
+	}
+	a.profileRuns.mu.Unlock()
+
+	wailsRuntime.EventsEmit(a.ctx, "log", formatProfileSummary(summary))
+	wailsRuntime.EventsEmit(a.ctx, "profile:done", summary)
+}
+
+// statusLabels are the result tags used in the profile summary.
+var statusLabels = map[string]string{
+	statusOK:          "[OK]",
+	statusFailed:      "[FAILED]",
+	statusTimeout:     "[TIMEOUT]",
+	statusCancelled:   "[CANCELLED]",
+	statusUnsupported: "[UNSUPPORTED]",
+}
+
+// formatProfileSummary renders the summary as a plain-text block for the
+// console and exported logs.
+func formatProfileSummary(s ProfileSummary) string {
+	var b strings.Builder
+	b.WriteString("=====================================\n")
+	fmt.Fprintf(&b, "[ Profile Summary: %s ]\n", s.ProfileName)
+	fmt.Fprintf(&b, "Finished : %s\n", s.End.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Duration : %s\n", time.Duration(s.DurationMs)*time.Millisecond)
+
+	var counts []string
+	for _, status := range []string{statusOK, statusFailed, statusTimeout, statusCancelled, statusUnsupported} {
+		if n := s.Counts[status]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Fprintf(&b, "Result   : %s\n", strings.Join(counts, ", "))
+	if s.Cancelled {
+		b.WriteString("Status   : Cancelled before all checks ran\n")
+	}
+	b.WriteString("=====================================")
+
+	for _, res := range s.Results {
+		line := fmt.Sprintf("\n%-13s %s (%s)", statusLabels[res.Status], res.CheckName, time.Duration(res.DurationMs)*time.Millisecond)
+		for _, step := range res.Steps {
+			if step.Error != "" {
+				line += fmt.Sprintf(" - step %d: %s", step.Index, step.Error)
+				break
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuiltinProfiles(t *testing.T) {
+	for _, p := range builtinProfiles {
+		for _, id := range p.Checks {
+			if lookupCheck(id) == nil {
+				t.Errorf("%s: unknown check %q", p.ID, id)
+			}
+		}
+	}
+}
+
+// Full audit must pick up new checks unless they open a window, modify the
+// machine or need the machine to themselves.
+func TestFullAuditCoversReadOnlyChecks(t *testing.T) {
+	i := slices.IndexFunc(builtinProfiles, func(p Profile) bool { return p.ID == "full-audit" })
+	if i < 0 {
+		t.Fatal("no full-audit profile")
+	}
+	for _, c := range checkRegistry {
+		readOnly := !c.OpensWindow && !c.Destructive && !c.Exclusive && !c.Custom
+		if included := slices.Contains(builtinProfiles[i].Checks, c.ID); readOnly != included {
+			t.Errorf("%s: in full-audit = %v, want %v", c.ID, included, readOnly)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "strings"
+
+// readProxySettings parses `scutil --proxy`, the proxy settings of the
+// primary network service. A failing command is reported as a failed step.
+func readProxySettings(r *checkRun) []ProxySetting {
+	out := r.capture(Step{Command: "scutil", Args: []string{"--proxy"}, Quiet: true})
+	return parseScutilProxy(strings.Join(out, "\n"))
+}
+
+// browserPolicyFiles lists the managed policy files of common browsers.
+// Chrome and Edge policies live in configuration profiles, which apply to
+// the system settings read by readProxySettings.
+func browserPolicyFiles() []browserStateFile {
+	return []browserStateFile{
+		{browser: "Mozilla Firefox", firefox: true, pattern: "/Applications/Firefox.app/Contents/Resources/distribution/policies.json"},
+	}
+}
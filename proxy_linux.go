@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gnomeProxySchema is installed with GNOME; without it gsettings fails.
+const gnomeProxySchema = "/usr/share/glib-2.0/schemas/org.gnome.system.proxy.gschema.xml"
+
+// readProxySettings reads /etc/environment and the desktop proxy settings
+// (GNOME via gsettings, KDE via kioslaverc).
+func readProxySettings(r *checkRun) []ProxySetting {
+	var settings []ProxySetting
+	if data, err := os.ReadFile("/etc/environment"); err == nil {
+		settings = append(settings, parseEnvironmentFile(string(data), "/etc/environment")...)
+	}
+	if hasCommand("gsettings") && exists(gnomeProxySchema) {
+		out := r.capture(Step{Command: "gsettings", Args: []string{"list-recursively", "org.gnome.system.proxy"}, Quiet: true})
+		settings = append(settings, parseGSettingsProxy(strings.Join(out, "\n"))...)
+	}
+	if data, err := os.ReadFile(filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "kioslaverc")); err == nil {
+		settings = append(settings, parseKioslaverc(string(data))...)
+	}
+	return settings
+}
+
+// browserPolicyFiles lists the managed policy files of common browsers.
+func browserPolicyFiles() []browserStateFile {
+	return []browserStateFile{
+		{browser: "Google Chrome", pattern: "/etc/opt/chrome/policies/managed/*.json"},
+		{browser: "Chromium", pattern: "/etc/chromium/policies/managed/*.json"},
+		{browser: "Microsoft Edge", pattern: "/etc/opt/edge/policies/managed/*.json"},
+		{browser: "Mozilla Firefox", firefox: true, pattern: "/etc/firefox/policies/policies.json"},
+		{browser: "Mozilla Firefox", firefox: true, pattern: "/usr/lib*/firefox*/distribution/policies.json"},
+	}
+}
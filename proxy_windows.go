@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Registry keys holding proxy settings: the per-user Internet Settings used
+// by WinINet, Edge and Chrome, and the Chrome and Edge policies.
+const (
+	internetSettingsKey = `HKCU:\Software\Microsoft\Windows\CurrentVersion\Internet Settings`
+	chromePolicyKey     = `HKLM:\SOFTWARE\Policies\Google\Chrome`
+	edgePolicyKey       = `HKLM:\SOFTWARE\Policies\Microsoft\Edge`
+)
+
+// proxyRegistryScript prints "[key]" and then "Name=Value" for each proxy
+// value set under the key. Missing keys print only the header.
+const proxyRegistryScript = "foreach ($k in '" + internetSettingsKey + "','" + chromePolicyKey + "','" + edgePolicyKey + "') { " +
+	"\"[$k]\"; $p = Get-ItemProperty -Path $k -ErrorAction SilentlyContinue; if ($p) { " +
+	"foreach ($n in 'ProxyEnable','ProxyServer','ProxyOverride','AutoConfigURL','ProxyMode','ProxyPacUrl') { " +
+	"if ($null -ne $p.$n) { \"$n=$($p.$n)\" } } } }"
+
+// readProxySettings reads the Chrome and Edge proxy policies and the
+// Internet Settings from the registry, and the WinHTTP proxy used by
+// services.
+func readProxySettings(r *checkRun) []ProxySetting {
+	step := powerShellStep("", proxyRegistryScript)
+	step.Quiet = true
+	keys := parseRegistrySections(strings.Join(r.capture(step), "\n"))
+
+	var settings []ProxySetting
+	for _, p := range []struct{ browser, key string }{{"Google Chrome", chromePolicyKey}, {"Microsoft Edge", edgePolicyKey}} {
+		v := keys[p.key]
+		if v["ProxyMode"] != "" || v["ProxyServer"] != "" || v["ProxyPacUrl"] != "" {
+			settings = append(settings, chromiumPolicyProxy(v["ProxyMode"], v["ProxyServer"], v["ProxyPacUrl"], p.browser+" policy")...)
+		}
+	}
+	settings = append(settings, windowsInternetProxy(keys[internetSettingsKey])...)
+
+	out := r.capture(Step{Command: "netsh", Args: []string{"winhttp", "show", "proxy"}, Quiet: true})
+	return append(settings, parseWinHTTPProxy(strings.Join(out, "\n"))...)
+}
+
+// browserPolicyFiles lists the managed policy files of common browsers;
+// Chrome and Edge policies are in the registry.
+func browserPolicyFiles() []browserStateFile {
+	return []browserStateFile{
+		{browser: "Mozilla Firefox", firefox: true, pattern: filepath.Join(os.Getenv("ProgramFiles"), "Mozilla Firefox", "distribution", "policies.json")},
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultMaxConcurrentRuns bounds how many checks execute at once. Slow
+// exam-lab machines struggle with more than a few PowerShell processes.
+const defaultMaxConcurrentRuns = 3
+
+// QueueEvent is the payload of the "run:queued" and "run:started" events.
+type QueueEvent struct {
+	RunID    string `json:"runId"`
+	CheckID  string `json:"checkId"`
+	Position int    `json:"position"` // 1-based queue position, 0 once started
+	Running  int    `json:"running"`
+	Queued   int    `json:"queued"`
+}
+
+// queuedRun is a run waiting for a worker slot.
+type queuedRun struct {
+	r    *checkRun
+	impl CheckFunc
+}
+
+// maxConcurrentRuns returns the configured worker pool size.
+func (a *App) maxConcurrentRuns() int {
+	a.settings.mu.RLock()
+	defer a.settings.mu.RUnlock()
+	if n := a.settings.settings.MaxConcurrentRuns; n > 0 {
+		return n
+	}
+	return defaultMaxConcurrentRuns
+}
+
+// enqueue submits a run. It starts immediately if a worker slot is free,
+// otherwise it waits in the queue and the frontend gets a "run:queued" event.
+func (m *runManager) enqueue(r *checkRun, impl CheckFunc) {
+	m.mu.Lock()
+	m.queue = append(m.queue, queuedRun{r: r, impl: impl})
+	ready := m.dispatchLocked(r.app.maxConcurrentRuns())
+	ev := m.queueEventLocked(r)
+	m.mu.Unlock()
+
+	if ev.Position > 0 {
+		wailsRuntime.EventsEmit(r.app.ctx, "run:queued", ev)
+		r.log(fmt.Sprintf("[INFO] %s queued (position %d, %d running).", r.check.Name, ev.Position, ev.Running))
+	}
+	m.begin(ready)
+}
+
+// dequeue removes a run that has not started yet. It reports whether the
+// run was still queued.
+func (m *runManager) dequeue(r *checkRun) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, q := range m.queue {
+		if q.r == r {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// release frees the worker slot of a finished run and starts whatever
+// can run next.
+func (m *runManager) release(r *checkRun) {
+	m.mu.Lock()
+	if !r.started {
+		m.mu.Unlock()
+		return
+	}
+	m.running--
+	if r.check.Exclusive {
+		m.exclusive = false
+	}
+	ready := m.dispatchLocked(r.app.maxConcurrentRuns())
+	m.mu.Unlock()
+
+	m.begin(ready)
+}
+
+// dispatchLocked takes runs off the head of the queue while slots are free.
+// The queue is strictly FIFO so an exclusive run waiting for the pool to
+// drain is not starved by later submissions.
+func (m *runManager) dispatchLocked(limit int) []queuedRun {
+	var ready []queuedRun
+	for len(m.queue) > 0 && !m.exclusive {
+		next := m.queue[0]
+		if next.r.check.Exclusive {
+			if m.running > 0 {
+				break
+			}
+			m.exclusive = true
+		} else if m.running >= limit {
+			break
+		}
+		m.queue = m.queue[1:]
+		m.running++
+		next.r.started = true
+		ready = append(ready, next)
+	}
+	return ready
+}
+
+// queueEventLocked describes the run's place in the queue.
+func (m *runManager) queueEventLocked(r *checkRun) QueueEvent {
+	ev := QueueEvent{RunID: r.id, CheckID: r.check.ID, Running: m.running, Queued: len(m.queue)}
+	for i, q := range m.queue {
+		if q.r == r {
+			ev.Position = i + 1
+			break
+		}
+	}
+	return ev
+}
+
+// begin starts the dispatched runs: it arms their timeouts, notifies the
+// frontend and executes them in the background.
+func (m *runManager) begin(ready []queuedRun) {
+	for _, q := range ready {
+		r := q.r
+		r.startTime = time.Now()
+		r.timer = time.AfterFunc(r.timeout, func() { r.cancel(errRunTimedOut) })
+
+		m.mu.Lock()
+		ev := m.queueEventLocked(r)
+		m.mu.Unlock()
+		wailsRuntime.EventsEmit(r.app.ctx, "run:started", ev)
+
+		go r.execute(q.impl)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// CheckFunc implements a check for a single platform.
+type CheckFunc func(r *checkRun)
+
+// platformAny is the Platforms key for an implementation shared by every OS.
+const platformAny = "*"
+
+// Check describes a single diagnostic that can be run from the UI.
+type Check struct {
+	ID          string // Stable identifier, used by the frontend and in logs
+	Name        string // Display name
+	Category    string // Category ID (see checkCategories)
+	Description string // One-line summary shown under the name
+	OpensWindow bool   // Launches an external GUI window
+	Screenshot  bool   // Capture a screenshot when the check finishes
+	Destructive bool   // Deletes or modifies data on the machine
+	Exclusive   bool   // Never runs concurrently with other checks
+	Custom      bool   // Loaded from the custom checks file
+
+	// Timeout bounds the run; zero means defaultCheckTimeout.
+	// It can be overridden per check in the settings file.
+	Timeout time.Duration
+
+	// Platforms maps runtime.GOOS (or platformAny) to the implementation.
+	Platforms map[string]CheckFunc
+}
+
+// Category groups checks in the UI.
+type Category struct {
+	ID    string
+	Title string
+	Icon  string
+}
+
+var checkCategories = []Category{
+	{ID: "network", Title: "Network", Icon: "📡"},
+	{ID: "application_system", Title: "Application / System", Icon: "💻"},
+	{ID: "malware_antivirus", Title: "Malware / Anti Virus", Icon: "🛡️"},
+	{ID: "remote_services", Title: "Remote Services", Icon: "🔗"},
+	{ID: "clean_files", Title: "Clean Files", Icon: "🧹"},
+	{ID: customCategory, Title: "Custom Checks", Icon: "🧩"},
+}
+
+// checkRegistry holds every registered check in registration order.
+var checkRegistry []*Check
+
+// registerCheck adds a check definition to the registry, together with its
+// Linux implementation from linuxChecks, if any.
+// It panics on duplicate IDs since that is always a programming error.
+func registerCheck(c Check) {
+	if lookupCheck(c.ID) != nil {
+		panic("duplicate check ID: " + c.ID)
+	}
+	if fn, ok := linuxChecks[c.ID]; ok {
+		c.Platforms["linux"] = fn
+	}
+	checkRegistry = append(checkRegistry, &c)
+}
+
+// lookupCheck finds a check by its ID or, for older callers, its display name.
+func lookupCheck(key string) *Check {
+	for _, c := range checkRegistry {
+		if c.ID == key || strings.EqualFold(c.Name, key) {
+			return c
+		}
+	}
+	return nil
+}
+
+// impl returns the implementation for the given OS, falling back to the
+// shared implementation. It returns nil if the check is unsupported.
+func (c *Check) impl(goos string) CheckFunc {
+	if fn, ok := c.Platforms[goos]; ok {
+		return fn
+	}
+	return c.Platforms[platformAny]
+}
+
+// platformNames maps runtime.GOOS values to user-facing names.
+var platformNames = map[string]string{
+	"darwin":  "macOS",
+	"windows": "Windows",
+	"linux":   "Linux",
+}
+
+// platformName returns the user-facing name for a GOOS value.
+func platformName(goos string) string {
+	if name, ok := platformNames[goos]; ok {
+		return name
+	}
+	return goos
+}
+
+// CatalogCheck is the frontend view of a registered check.
+type CatalogCheck struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	OpensWindow bool     `json:"opensWindow"`
+	Destructive bool     `json:"destructive"`
+	Exclusive   bool     `json:"exclusive"`
+	Custom      bool     `json:"custom"`
+	Supported   bool     `json:"supported"`
+	Timeout     int      `json:"timeoutSeconds"`   // Effective timeout, including settings overrides
+	Reason      string   `json:"reason,omitempty"` // Why the check is unavailable
+	Platforms   []string `json:"platforms"`        // Platforms with an implementation
+}
+
+// CatalogCategory is a category together with its checks.
+type CatalogCategory struct {
+	ID     string         `json:"id"`
+	Title  string         `json:"title"`
+	Icon   string         `json:"icon"`
+	Checks []CatalogCheck `json:"checks"`
+}
+
+// Catalog describes every check available to the UI on the running OS.
+type Catalog struct {
+	Platform   string            `json:"platform"`
+	Categories []CatalogCategory `json:"categories"`
+}
+
+// supportedPlatforms lists the user-facing names of the platforms the check runs on.
+func (c *Check) supportedPlatforms() []string {
+	if _, ok := c.Platforms[platformAny]; ok {
+		return []string{"All"}
+	}
+	var names []string
+	for _, goos := range []string{"windows", "darwin", "linux"} {
+		if _, ok := c.Platforms[goos]; ok {
+			names = append(names, platformName(goos))
+		}
+	}
+	return names
+}
+
+// buildCatalog groups the registry by category for the given OS.
+func buildCatalog(goos string, timeoutFor func(*Check) time.Duration) Catalog {
+	catalog := Catalog{Platform: platformName(goos)}
+	for _, cat := range checkCategories {
+		entry := CatalogCategory{ID: cat.ID, Title: cat.Title, Icon: cat.Icon, Checks: []CatalogCheck{}}
+		for _, c := range checkRegistry {
+			if c.Category != cat.ID {
+				continue
+			}
+			item := CatalogCheck{
+				ID:          c.ID,
+				Name:        c.Name,
+				Description: c.Description,
+				OpensWindow: c.OpensWindow,
+				Destructive: c.Destructive,
+				Exclusive:   c.Exclusive,
+				Custom:      c.Custom,
+				Supported:   c.impl(goos) != nil,
+				Timeout:     int(timeoutFor(c).Seconds()),
+				Platforms:   c.supportedPlatforms(),
+			}
+			if !item.Supported {
+				item.Reason = fmt.Sprintf("Not available on %s (supported: %s)", platformName(goos), strings.Join(item.Platforms, ", "))
+			}
+			entry.Checks = append(entry.Checks, item)
+		}
+		if len(entry.Checks) == 0 {
+			continue
+		}
+		catalog.Categories = append(catalog.Categories, entry)
+	}
+	return catalog
+}
+
+// GetCatalog returns the categories and checks for the running OS
+func (a *App) GetCatalog() Catalog {
+	return buildCatalog(runtime.GOOS, a.timeoutFor)
+}
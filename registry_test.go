@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+// A misspelled ID in linuxChecks would silently drop Linux support.
+func TestLinuxChecksRegistered(t *testing.T) {
+	for id := range linuxChecks {
+		c := lookupCheck(id)
+		if c == nil {
+			t.Errorf("linuxChecks: unknown check ID %q", id)
+		} else if _, ok := c.Platforms["linux"]; !ok {
+			t.Errorf("%s: Linux implementation not registered", id)
+		}
+	}
+}
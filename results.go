@@ -0,0 +1,109 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// StepResult records the outcome of a single command within a run.
+type StepResult struct {
+	Index       int       `json:"index"`
+	Label       string    `json:"label,omitempty"`
+	Command     string    `json:"command"`
+	Args        []string  `json:"args,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	DurationMs  int64     `json:"durationMs"`
+	ExitCode    int       `json:"exitCode"` // -1 if the process did not start or was killed
+	Error       string    `json:"error,omitempty"`
+	StdoutLines int       `json:"stdoutLines"`
+	StderrLines int       `json:"stderrLines"`
+}
+
+// RunResult records the outcome of a run. Results are kept in memory for
+// the session and returned by GetRunResults.
+type RunResult struct {
+	RunID       string       `json:"runId"`
+	CheckID     string       `json:"checkId"`
+	CheckName   string       `json:"checkName"`
+	Status      string       `json:"status"` // ok, failed, timeout, cancelled or unsupported
+	Start       time.Time    `json:"start"`
+	End         time.Time    `json:"end"`
+	DurationMs  int64        `json:"durationMs"`
+	Steps       []StepResult `json:"steps"`
+	StdoutLines int          `json:"stdoutLines"`
+	StderrLines int          `json:"stderrLines"`
+
+	// Data holds structured output of checks that produce it (for example
+	// "interfaces"), keyed by kind. It is included in JSON exports.
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// result builds the run's result record with the given final status.
+func (r *checkRun) result(status string) RunResult {
+	r.mu.Lock()
+	steps := append([]StepResult{}, r.stepResults...)
+	data := r.data
+	r.mu.Unlock()
+
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Index < steps[j].Index })
+
+	end := time.Now()
+	res := RunResult{
+		RunID:      r.id,
+		CheckID:    r.check.ID,
+		CheckName:  r.check.Name,
+		Status:     status,
+		Start:      r.startTime,
+		End:        end,
+		DurationMs: end.Sub(r.startTime).Milliseconds(),
+		Steps:      steps,
+		Data:       data,
+	}
+	for _, s := range steps {
+		res.StdoutLines += s.StdoutLines
+		res.StderrLines += s.StderrLines
+	}
+	return res
+}
+
+// setData attaches structured output to the run's result under key.
+func (r *checkRun) setData(key string, v any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		r.data = make(map[string]any)
+	}
+	r.data[key] = v
+}
+
+// Finding is a problem found while analysing a check's structured output.
+type Finding struct {
+	Level   string `json:"level"` // warn or error
+	Message string `json:"message"`
+}
+
+// report logs warn findings and records error findings as failed steps.
+func (r *checkRun) report(findings []Finding) {
+	for _, f := range findings {
+		if f.Level == levelError {
+			r.fail(f.Message)
+		} else {
+			r.log("[WARN] " + f.Message)
+		}
+	}
+}
+
+// record stores a finished run's result for the session.
+func (m *runManager) record(res RunResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, res)
+}
+
+// GetRunResults returns the results of every finished run this session, oldest first
+func (a *App) GetRunResults() []RunResult {
+	a.runs.mu.Lock()
+	defer a.runs.mu.Unlock()
+	return append([]RunResult{}, a.runs.results...)
+}
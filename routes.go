@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Address families of a route.
+const (
+	familyIPv4 = "ipv4"
+	familyIPv6 = "ipv6"
+)
+
+// Route is one entry of the routing table.
+type Route struct {
+	Family      string `json:"family"`
+	Destination string `json:"destination"`       // CIDR; 0.0.0.0/0 or ::/0 for default routes
+	Gateway     string `json:"gateway,omitempty"` // Empty for on-link routes
+	Interface   string `json:"interface"`
+	Metric      int    `json:"metric"`
+}
+
+// isDefault reports whether the route is a default route.
+func (rt Route) isDefault() bool {
+	return rt.Destination == "0.0.0.0/0" || rt.Destination == "::/0"
+}
+
+// RouteTable is the structured output of the "Cek Routing" check.
+type RouteTable struct {
+	Routes   []Route   `json:"routes"`
+	Findings []Finding `json:"findings"`
+}
+
+// checkRoutes implements "Cek Routing": it reads the routing table (see
+// readRoutes), prints it and reports problems with the default gateway.
+func checkRoutes(r *checkRun) {
+	r.header("Status : Reading routing table...")
+	routes, err := readRoutes(r)
+	if r.stopped() {
+		return
+	}
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read routing table: %v", err))
+		return
+	}
+
+	table := RouteTable{Routes: routes, Findings: analyzeRoutes(routes)}
+	r.setData("routes", table)
+	r.log(formatRouteTable(routes))
+
+	if len(table.Findings) == 0 {
+		r.log("[OK] Exactly one default gateway and no tunnel routes.")
+	}
+	r.report(table.Findings)
+}
+
+// formatRouteTable renders routes as an aligned text table.
+func formatRouteTable(routes []Route) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FAMILY\tDESTINATION\tGATEWAY\tINTERFACE\tMETRIC")
+	for _, rt := range routes {
+		gateway := rt.Gateway
+		if gateway == "" {
+			gateway = "on-link"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", rt.Family, rt.Destination, gateway, rt.Interface, rt.Metric)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// tunnelInterface matches interface names used by VPN and tunnel drivers
+// (OpenVPN tun/tap, WireGuard, macOS utun, PPP, IPsec, GlobalProtect, ...).
+var tunnelInterface = regexp.MustCompile(`(?i)^(tun|tap|utun|wg|ppp|ipsec|gpd|zt|tailscale|nordlynx)\d*|vpn|wireguard|tunnel|anyconnect|fortinet`)
+
+// analyzeRoutes flags a missing or ambiguous default gateway and routes
+// that send traffic through a tunnel interface.
+func analyzeRoutes(routes []Route) []Finding {
+	var findings []Finding
+	warn := func(format string, args ...any) {
+		findings = append(findings, Finding{Level: levelWarn, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var defaults []Route
+	hasHalf := map[string]bool{}
+	for _, rt := range routes {
+		if rt.Family == familyIPv4 && rt.isDefault() {
+			defaults = append(defaults, rt)
+		}
+		if rt.Destination == "0.0.0.0/1" || rt.Destination == "128.0.0.0/1" {
+			hasHalf[rt.Destination] = true
+		}
+	}
+
+	switch {
+	case len(defaults) == 0 && len(hasHalf) < 2:
+		findings = append(findings, Finding{Level: levelError, Message: "No IPv4 default route: other networks and the internet are unreachable."})
+	case len(defaults) > 1:
+		var list []string
+		for _, rt := range defaults {
+			list = append(list, fmt.Sprintf("%s via %s (metric %d)", rt.Gateway, rt.Interface, rt.Metric))
+		}
+		warn("%d IPv4 default routes: %s.", len(defaults), strings.Join(list, ", "))
+	}
+	if len(hasHalf) == 2 {
+		warn("0.0.0.0/1 and 128.0.0.0/1 routes override the default gateway (full-tunnel VPN).")
+	}
+
+	tunnels := map[string]int{}
+	for _, rt := range routes {
+		if !tunnelInterface.MatchString(rt.Interface) || isLinkLocalOrMulticast(rt.Destination) {
+			continue
+		}
+		if gw := net.ParseIP(rt.Gateway); gw != nil && gw.IsLinkLocalUnicast() {
+			continue // macOS keeps IPv6 routes via fe80:: on its system utun interfaces
+		}
+		tunnels[rt.Interface]++
+	}
+	names := make([]string, 0, len(tunnels))
+	for name := range tunnels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		warn("%d route(s) through tunnel interface %s (VPN or tunnel active).", tunnels[name], name)
+	}
+	return findings
+}
+
+// isLinkLocalOrMulticast reports whether the destination network is
+// link-local or multicast; every interface has those, tunnels included.
+func isLinkLocalOrMulticast(cidr string) bool {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	return ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// defaultGateways returns the IPv4 default gateway per interface. The
+// first route wins, matching the kernel's order.
+func defaultGateways(routes []Route) map[string]string {
+	gateways := make(map[string]string)
+	for _, rt := range routes {
+		if rt.Family != familyIPv4 || !rt.isDefault() || rt.Gateway == "" {
+			continue
+		}
+		if _, ok := gateways[rt.Interface]; !ok {
+			gateways[rt.Interface] = rt.Gateway
+		}
+	}
+	return gateways
+}
+
+// parseProcRoutes parses /proc/net/route. Addresses there are
+// little-endian hex.
+func parseProcRoutes(data string) []Route {
+	var routes []Route
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		dest, gw, mask := parseHexIPv4(fields[1]), parseHexIPv4(fields[2]), parseHexIPv4(fields[7])
+		if dest == nil || gw == nil || mask == nil {
+			continue
+		}
+		ones, _ := net.IPMask(mask).Size()
+		metric, _ := strconv.Atoi(fields[6])
+		rt := Route{Family: familyIPv4, Destination: fmt.Sprintf("%s/%d", dest, ones), Interface: fields[0], Metric: metric}
+		if !gw.Equal(net.IPv4zero) {
+			rt.Gateway = gw.String()
+		}
+		routes = append(routes, rt)
+	}
+	return routes
+}
+
+// rtfReject marks unreachable routes in /proc/net/ipv6_route.
+const rtfReject = 0x0200
+
+// parseProcIPv6Routes parses /proc/net/ipv6_route, skipping rejected routes
+// and the host routes the kernel adds on the loopback interface.
+func parseProcIPv6Routes(data string) []Route {
+	var routes []Route
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		// dest prefixlen src srcprefixlen nexthop metric refcnt use flags iface
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[8], 16, 32)
+		if flags&rtfReject != 0 || fields[9] == "lo" {
+			continue
+		}
+		dest, next := parseHexIPv6(fields[0]), parseHexIPv6(fields[4])
+		prefix, err := strconv.ParseUint(fields[1], 16, 8)
+		if dest == nil || next == nil || err != nil {
+			continue
+		}
+		metric, _ := strconv.ParseUint(fields[5], 16, 32)
+		rt := Route{Family: familyIPv6, Destination: fmt.Sprintf("%s/%d", dest, prefix), Interface: fields[9], Metric: int(metric)}
+		if !next.Equal(net.IPv6unspecified) {
+			rt.Gateway = next.String()
+		}
+		routes = append(routes, rt)
+	}
+	return routes
+}
+
+// parseHexIPv4 decodes a little-endian hex IPv4 address as used in /proc/net.
+func parseHexIPv4(s string) net.IP {
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw) != 4 {
+		return nil
+	}
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+	return ip
+}
+
+// parseHexIPv6 decodes a 32-digit hex IPv6 address as used in /proc/net.
+func parseHexIPv6(s string) net.IP {
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw) != net.IPv6len {
+		return nil
+	}
+	return net.IP(raw)
+}
+
+// parseNetstatRoutes parses the output of `netstat -nr` on macOS, which
+// lists an "Internet:" and an "Internet6:" section.
+func parseNetstatRoutes(out string) []Route {
+	var routes []Route
+	family := ""
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "Internet:":
+			family = familyIPv4
+			continue
+		case line == "Internet6:":
+			family = familyIPv6
+			continue
+		case family == "" || line == "" || strings.HasPrefix(line, "Destination"):
+			continue
+		}
+
+		// Destination Gateway Flags Netif [Expire]
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		dest := normalizeNetstatDest(fields[0], family, strings.Contains(fields[2], "H"))
+		if dest == "" {
+			continue
+		}
+		rt := Route{Family: family, Destination: dest, Interface: fields[3]}
+		if gw := stripZone(fields[1]); net.ParseIP(gw) != nil {
+			rt.Gateway = gw // Otherwise link#N or a MAC address: on-link
+		}
+		routes = append(routes, rt)
+	}
+	return routes
+}
+
+// normalizeNetstatDest turns a BSD netstat destination ("default",
+// "192.168.1", "10/8", "fe80::%lo0/64", a host address) into CIDR notation.
+func normalizeNetstatDest(dest, family string, host bool) string {
+	if dest == "default" {
+		if family == familyIPv6 {
+			return "::/0"
+		}
+		return "0.0.0.0/0"
+	}
+
+	addr, prefix, hasPrefix := strings.Cut(stripZone(dest), "/")
+	if family == familyIPv6 {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return ""
+		}
+		if !hasPrefix {
+			prefix = "128"
+		}
+		return ip.String() + "/" + prefix
+	}
+
+	// BSD abbreviates IPv4 networks by dropping trailing zero octets
+	octets := strings.Split(addr, ".")
+	if len(octets) > 4 {
+		return ""
+	}
+	if !hasPrefix {
+		prefix = strconv.Itoa(8 * len(octets))
+		if host || len(octets) == 4 {
+			prefix = "32"
+		}
+	}
+	for len(octets) < 4 {
+		octets = append(octets, "0")
+	}
+	ip := net.ParseIP(strings.Join(octets, "."))
+	if ip == nil {
+		return ""
+	}
+	return ip.String() + "/" + prefix
+}
+
+// stripZone removes an IPv6 zone ("%en0") from an address or network.
+func stripZone(s string) string {
+	if i := strings.Index(s, "%"); i >= 0 {
+		rest := s[i:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			return s[:i] + rest[j:]
+		}
+		return s[:i]
+	}
+	return s
+}
+
+// parseRoutePrint parses the output of `route print` on Windows. IPv4 rows
+// are "destination netmask gateway interface metric"; IPv6 rows are
+// "ifindex metric destination gateway", with the gateway wrapped onto the
+// next line when the destination is long.
+func parseRoutePrint(out string) []Route {
+	var routes []Route
+	family := ""
+	active := false
+	var pending []string // IPv6 row waiting for its wrapped gateway
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "IPv4 Route Table"):
+			family, active = familyIPv4, false
+			continue
+		case strings.HasPrefix(line, "IPv6 Route Table"):
+			family, active = familyIPv6, false
+			continue
+		case strings.HasPrefix(line, "Active Routes:"):
+			active = true
+			continue
+		case strings.HasPrefix(line, "Persistent Routes:"), strings.HasPrefix(line, "===="):
+			active = false
+			continue
+		case !active || line == "" || strings.HasPrefix(line, "Network Destination") || strings.HasPrefix(line, "If Metric"):
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch family {
+		case familyIPv4:
+			if len(fields) != 5 {
+				continue
+			}
+			ip, mask := net.ParseIP(fields[0]), net.ParseIP(fields[1]).To4()
+			metric, err := strconv.Atoi(fields[4])
+			if ip == nil || mask == nil || err != nil {
+				continue
+			}
+			ones, _ := net.IPMask(mask).Size()
+			rt := Route{Family: familyIPv4, Destination: fmt.Sprintf("%s/%d", ip, ones), Interface: fields[3], Metric: metric}
+			if fields[2] != "On-link" {
+				rt.Gateway = fields[2]
+			}
+			routes = append(routes, rt)
+
+		case familyIPv6:
+			if pending != nil {
+				fields = append(pending, fields...)
+				pending = nil
+			}
+			if len(fields) == 3 {
+				pending = fields
+				continue
+			}
+			if len(fields) != 4 {
+				continue
+			}
+			metric, err := strconv.Atoi(fields[1])
+			_, ipnet, cidrErr := net.ParseCIDR(stripZone(fields[2]))
+			if err != nil || cidrErr != nil {
+				continue
+			}
+			ones, _ := ipnet.Mask.Size()
+			rt := Route{Family: familyIPv6, Destination: fmt.Sprintf("%s/%d", ipnet.IP, ones), Interface: fields[0], Metric: metric}
+			if fields[3] != "On-link" {
+				rt.Gateway = stripZone(fields[3])
+			}
+			routes = append(routes, rt)
+		}
+	}
+	return routes
+}
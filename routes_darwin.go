@@ -0,0 +1,16 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// readRoutes parses the output of `netstat -nr`.
+func readRoutes(r *checkRun) ([]Route, error) {
+	out := r.capture(Step{Command: "netstat", Args: []string{"-nr"}, Quiet: true})
+	routes := parseNetstatRoutes(strings.Join(out, "\n"))
+	if len(routes) == 0 {
+		return nil, errors.New("no routes in netstat output")
+	}
+	return routes, nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import "os"
+
+// readRoutes reads the IPv4 and IPv6 routing tables from /proc/net.
+func readRoutes(r *checkRun) ([]Route, error) {
+	data, err := os.ReadFile("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	routes := parseProcRoutes(string(data))
+
+	// IPv6 may be disabled, in which case the file is missing
+	if data, err := os.ReadFile("/proc/net/ipv6_route"); err == nil {
+		routes = append(routes, parseProcIPv6Routes(string(data))...)
+	}
+	return routes, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouteParsers(t *testing.T) {
+	v4 := func(dest, gw, iface string, metric int) Route {
+		return Route{Family: familyIPv4, Destination: dest, Gateway: gw, Interface: iface, Metric: metric}
+	}
+	v6 := func(dest, gw, iface string, metric int) Route {
+		return Route{Family: familyIPv6, Destination: dest, Gateway: gw, Interface: iface, Metric: metric}
+	}
+	tests := []struct {
+		fixture string
+		parse   func(string) []Route
+		want    []Route
+	}{
+		{"proc_net_route.txt", parseProcRoutes, []Route{
+			v4("0.0.0.0/0", "192.168.1.1", "wlp2s0", 600),
+			v4("0.0.0.0/0", "10.0.10.254", "enp0s31f6", 100),
+			v4("10.0.0.0/8", "", "wg0", 0),
+			v4("10.0.10.0/24", "", "enp0s31f6", 100),
+			v4("169.254.0.0/16", "", "wlp2s0", 1000),
+			v4("192.168.1.0/24", "", "wlp2s0", 600),
+		}},
+		{"proc_net_ipv6_route.txt", parseProcIPv6Routes, []Route{
+			v6("2001:db8:1::/64", "", "wlp2s0", 600),
+			v6("fe80::/64", "", "wlp2s0", 256),
+			v6("::/0", "fe80::211:22ff:fe33:4455", "wlp2s0", 600),
+			v6("ff00::/8", "", "wlp2s0", 256),
+		}},
+		{"netstat_nr_darwin.txt", parseNetstatRoutes, []Route{
+			v4("0.0.0.0/0", "192.168.1.1", "en0", 0),
+			v4("10.8.0.1/32", "10.8.0.5", "utun4", 0),
+			v4("127.0.0.0/8", "127.0.0.1", "lo0", 0),
+			v4("127.0.0.1/32", "127.0.0.1", "lo0", 0),
+			v4("169.254.0.0/16", "", "en0", 0),
+			v4("192.168.1.0/24", "", "en0", 0),
+			v4("192.168.1.1/32", "", "en0", 0),
+			v4("192.168.1.1/32", "", "en0", 0),
+			v4("192.168.1.23/32", "", "en0", 0),
+			v4("224.0.0.0/4", "", "en0", 0),
+			v4("255.255.255.255/32", "", "en0", 0),
+			v6("::/0", "fe80::", "utun0", 0),
+			v6("::/0", "fe80::a691:b1ff:fe2c:3d4e", "en0", 0),
+			v6("::1/128", "::1", "lo0", 0),
+			v6("2001:db8:1::/64", "", "en0", 0),
+			v6("fe80::/64", "fe80::1", "lo0", 0),
+			v6("fe80::1/128", "", "lo0", 0),
+			v6("fe80::/64", "", "en0", 0),
+			v6("fe80::/64", "fe80::8c2b:37ff:fe5a:1b2c", "utun0", 0),
+			v6("ff00::/8", "::1", "lo0", 0),
+		}},
+		{"route_print_windows.txt", parseRoutePrint, []Route{
+			v4("0.0.0.0/0", "192.168.1.1", "192.168.1.50", 25),
+			v4("127.0.0.0/8", "", "127.0.0.1", 331),
+			v4("127.0.0.1/32", "", "127.0.0.1", 331),
+			v4("192.168.1.0/24", "", "192.168.1.50", 281),
+			v4("192.168.1.50/32", "", "192.168.1.50", 281),
+			v4("224.0.0.0/4", "", "127.0.0.1", 331),
+			v4("255.255.255.255/32", "", "192.168.1.50", 281),
+			v6("::/0", "fe80::a691:b1ff:fe2c:3d4e", "12", 281),
+			v6("::1/128", "", "1", 331),
+			v6("2001:db8:1::/64", "", "12", 281),
+			v6("2001:db8:1:0:1c3a:5e7f:9b2d:4c61/128", "", "12", 281), // Gateway wrapped onto the next line
+			v6("fe80::/64", "", "12", 281),
+			v6("ff00::/8", "", "1", 331),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := tt.parse(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %d routes:\n%s\nwant %d:\n%s", len(got), formatRouteTable(got), len(tt.want), formatRouteTable(tt.want))
+			}
+		})
+	}
+}
+
+func TestAnalyzeRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+		want   []string // "level: message substring", in order
+	}{
+		{"single default", parseNetstatRoutes(readFixture(t, "netstat_nr_darwin.txt")), []string{
+			// utun0 only carries link-local IPv6 routes and is not reported
+			"warn: 1 route(s) through tunnel interface utun4",
+		}},
+		{"multiple defaults and a tunnel", parseProcRoutes(readFixture(t, "proc_net_route.txt")), []string{
+			"warn: 2 IPv4 default routes: 192.168.1.1 via wlp2s0 (metric 600), 10.0.10.254 via enp0s31f6 (metric 100)",
+			"warn: 1 route(s) through tunnel interface wg0",
+		}},
+		{"no default", []Route{
+			{Family: familyIPv4, Destination: "192.168.1.0/24", Interface: "eth0"},
+			{Family: familyIPv6, Destination: "::/0", Gateway: "fe80::1", Interface: "eth0"},
+		}, []string{
+			"error: No IPv4 default route",
+		}},
+		{"full-tunnel VPN", []Route{
+			{Family: familyIPv4, Destination: "0.0.0.0/1", Gateway: "10.8.0.1", Interface: "tun0"},
+			{Family: familyIPv4, Destination: "128.0.0.0/1", Gateway: "10.8.0.1", Interface: "tun0"},
+			{Family: familyIPv4, Destination: "192.168.1.0/24", Interface: "eth0"},
+		}, []string{
+			"warn: 0.0.0.0/1 and 128.0.0.0/1 routes override the default gateway",
+			"warn: 2 route(s) through tunnel interface tun0",
+		}},
+		{"windows", parseRoutePrint(readFixture(t, "route_print_windows.txt")), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, analyzeRoutes(tt.routes), tt.want)
+		})
+	}
+}
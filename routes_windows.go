@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// readRoutes parses the output of `route print`. It lists interfaces by
+// address (IPv4) or index (IPv6); both are replaced by the adapter name.
+func readRoutes(r *checkRun) ([]Route, error) {
+	out := r.capture(Step{Command: "route", Args: []string{"print"}, Quiet: true})
+	routes := parseRoutePrint(strings.Join(out, "\n"))
+	if len(routes) == 0 {
+		return nil, errors.New("no routes in route print output")
+	}
+
+	names := adapterNames()
+	for i := range routes {
+		if name, ok := names[routes[i].Interface]; ok {
+			routes[i].Interface = name
+		}
+	}
+	return routes, nil
+}
+
+// adapterNames maps interface indexes (decimal) and addresses to adapter
+// names, as Windows tools identify interfaces by either.
+func adapterNames() map[string]string {
+	names := make(map[string]string)
+	ifaces, _ := net.Interfaces()
+	for _, iface := range ifaces {
+		names[strconv.Itoa(iface.Index)] = iface.Name
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok {
+				names[ipnet.IP.String()] = iface.Name
+			}
+		}
+	}
+	return names
+}
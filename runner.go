@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Run statuses reported in "done" events.
+const (
+	statusOK          = "ok"
+	statusFailed      = "failed"
+	statusCancelled   = "cancelled"
+	statusTimeout     = "timeout"
+	statusUnsupported = "unsupported"
+)
+
+// DoneEvent is the payload of the "done" event, emitted exactly once per run.
+type DoneEvent struct {
+	RunID   string `json:"runId"`
+	CheckID string `json:"checkId"`
+	Status  string `json:"status"`
+}
+
+// Cancellation causes, used to tell user cancellation from timeouts.
+var (
+	errRunCancelled = errors.New("run cancelled")
+	errRunTimedOut  = errors.New("run timed out")
+)
+
+// runManager tracks queued and in-flight runs so they can be scheduled and cancelled.
+type runManager struct {
+	mu      sync.Mutex
+	nextID  int
+	active  map[string]*checkRun
+	results []RunResult // Finished runs, oldest first
+
+	queue     []queuedRun // Runs waiting for a worker slot, in submission order
+	running   int         // Runs currently executing
+	exclusive bool        // An exclusive run is executing
+}
+
+func newRunManager() *runManager {
+	return &runManager{active: make(map[string]*checkRun)}
+}
+
+// start registers a new run for the check.
+func (m *runManager) start(a *App, check *Check) *checkRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	ctx, cancel := context.WithCancelCause(a.ctx)
+	r := &checkRun{
+		id:        fmt.Sprintf("run-%d", m.nextID),
+		app:       a,
+		check:     check,
+		ctx:       ctx,
+		cancel:    cancel,
+		timeout:   a.timeoutFor(check),
+		startTime: time.Now(),
+		finished:  make(chan struct{}),
+	}
+	m.active[r.id] = r
+	return r
+}
+
+// get returns the active run with the given ID, or nil.
+func (m *runManager) get(id string) *checkRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active[id]
+}
+
+// finish removes the run from the active set.
+func (m *runManager) finish(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.active, id)
+}
+
+// ExecuteCommand starts the check registered under the given ID (or display name)
+// and returns the run ID used by CancelRun and the "done" event.
+func (a *App) ExecuteCommand(feature string) (string, error) {
+	check := lookupCheck(feature)
+	if check == nil {
+		return "", fmt.Errorf("unknown feature: %s", feature)
+	}
+	return a.submit(check).id, nil
+}
+
+// submit creates a run for the check and queues it. Unsupported checks
+// finish immediately with statusUnsupported.
+func (a *App) submit(check *Check) *checkRun {
+	r := a.runs.start(a, check)
+
+	impl := check.impl(runtime.GOOS)
+	if impl == nil {
+		r.log(fmt.Sprintf("[ERROR] %s is not supported on %s.", check.Name, runtime.GOOS))
+		r.finish(statusUnsupported)
+		return r
+	}
+
+	a.runs.enqueue(r, impl)
+	return r
+}
+
+// CancelRun stops a running or queued check, killing any processes it started.
+// Passing a profile run ID stops the profile and its current check.
+func (a *App) CancelRun(runID string) error {
+	if a.cancelProfile(runID) {
+		return nil
+	}
+
+	r := a.runs.get(runID)
+	if r == nil {
+		return fmt.Errorf("run %s is not active", runID)
+	}
+	r.log(fmt.Sprintf("[CANCELLED] %s: cancellation requested.", r.check.Name))
+	r.cancel(errRunCancelled)
+
+	// A queued run has no goroutine to notice the cancellation
+	if a.runs.dequeue(r) {
+		r.finish(statusCancelled)
+	}
+	return nil
+}
+
+// checkRun carries the state of a single check invocation.
+type checkRun struct {
+	id      string
+	app     *App
+	check   *Check
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timeout time.Duration
+	timer   *time.Timer // Enforces the timeout once the run starts
+
+	started    bool // Left the queue (guarded by runManager.mu)
+	startTime  time.Time
+	finished   chan struct{} // Closed once the run has finished
+	res        RunResult     // Final result, valid once finished is closed
+	headerOnce sync.Once
+	doneOnce   sync.Once
+
+	mu          sync.Mutex
+	stepResults []StepResult // Commands started, in completion order
+	failedSteps int          // Commands (or internal steps) that failed
+	nextStep    int
+	data        map[string]any // Structured output, see setData
+}
+
+// Step is a single command within a multi-step check.
+type Step struct {
+	Label   string // Prefix for output lines when run in parallel
+	Command string
+	Args    []string
+	Quiet   bool // Capture stdout without emitting it, for output that is parsed
+
+	// EmptyExitOK treats exit status 1 without any output as success, for
+	// tools that report "nothing found" that way (lsof, grep)
+	EmptyExitOK bool
+
+	// OnStdout, if set, is called with each stdout line as it arrives
+	OnStdout func(line string)
+}
+
+// powerShellStep builds a step that runs a PowerShell command securely and silently.
+func powerShellStep(label, psCommand string) Step {
+	// -NoProfile: No user profile loaded
+	// -NonInteractive: No prompt
+	// -NoLogo: Hides version banner (though we are capturing output anyway)
+	// -Command: The actual code
+	return Step{Label: label, Command: "powershell", Args: []string{"-NoProfile", "-NonInteractive", "-NoLogo", "-Command", psCommand}}
+}
+
+// cancelled reports whether the run has been cancelled (by the user or on shutdown).
+func (r *checkRun) cancelled() bool {
+	return r.stopped() && !r.timedOut()
+}
+
+// timedOut reports whether the run exceeded its timeout.
+func (r *checkRun) timedOut() bool {
+	return errors.Is(context.Cause(r.ctx), errRunTimedOut)
+}
+
+// stopped reports whether the run was cancelled or timed out.
+func (r *checkRun) stopped() bool {
+	return r.ctx.Err() != nil
+}
+
+// finish signals the frontend that the run has finished. Only the first
+// call has any effect, so every run emits exactly one "done" event.
+func (r *checkRun) finish(status string) {
+	r.doneOnce.Do(func() {
+		if r.cancelled() {
+			status = statusCancelled
+		} else if r.timedOut() {
+			status = statusTimeout
+			r.log(fmt.Sprintf("[TIMEOUT] %s did not finish within %s and was stopped.", r.check.Name, r.timeout))
+		}
+		if r.timer != nil {
+			r.timer.Stop()
+		}
+		r.res = r.result(status)
+		r.app.runs.finish(r.id)
+		r.app.runs.record(r.res)
+		r.cancel(nil)
+		wailsRuntime.EventsEmit(r.app.ctx, "done", DoneEvent{RunID: r.id, CheckID: r.check.ID, Status: status})
+		r.app.runs.release(r)
+		close(r.finished)
+	})
+}
+
+// header emits the section header for the check. Only the first call has
+// any effect, so steps can request the default header unconditionally.
+func (r *checkRun) header(lines ...string) {
+	r.headerOnce.Do(func() {
+		lines = append([]string{"Time : " + r.startTime.Format("2006-01-02 15:04:05")}, lines...)
+		r.log(fmt.Sprintf("=====================================\n[ %s ]\n%s\n=====================================", r.check.Name, strings.Join(lines, "\n")))
+	})
+}
+
+// fail records a failed step that did not come from a command.
+func (r *checkRun) fail(msg string) {
+	r.mu.Lock()
+	r.failedSteps++
+	r.mu.Unlock()
+	r.log("[ERROR] " + msg)
+}
+
+// execute runs the check's steps in order and emits a single aggregate
+// "done" event once every step has finished.
+func (r *checkRun) execute(impl CheckFunc) {
+	impl(r)
+
+	r.mu.Lock()
+	steps, failed := len(r.stepResults), r.failedSteps
+	r.mu.Unlock()
+
+	status := statusOK
+	switch {
+	case r.cancelled():
+		status = statusCancelled
+		r.log(fmt.Sprintf("\n[CANCELLED] %s was stopped before completion.", r.check.Name))
+	case r.timedOut():
+		status = statusTimeout
+	case failed > 0:
+		status = statusFailed
+		r.log(fmt.Sprintf("\n[STOP] %d of %d steps failed.", failed, max(steps, failed)))
+	default:
+		r.log("\n[OK] Process completed successfully.")
+	}
+
+	// SCREENSHOT TRIGGER: on failure, or for checks flagged in the registry
+	// (typically those that open an external window)
+	if status == statusFailed || (status == statusOK && r.check.Screenshot) {
+		r.app.captureScreenshot(r.check.Name, r.log)
+	}
+
+	// Minimum delay for visible UX
+	elapsed := time.Since(r.startTime)
+	if elapsed < 700*time.Millisecond {
+		time.Sleep(700*time.Millisecond - elapsed)
+	}
+
+	r.finish(status)
+}
+
+// stream runs a command as the next step of the check and streams its output.
+// It blocks until the command exits, so consecutive calls run in order.
+func (r *checkRun) stream(command string, args ...string) {
+	r.runStep(Step{Command: command, Args: args}, false, nil)
+}
+
+// powerShell runs a PowerShell command as the next step of the check
+func (r *checkRun) powerShell(psCommand string) {
+	r.runStep(powerShellStep("", psCommand), false, nil)
+}
+
+// capture runs a step like stream and also returns its stdout lines, for
+// checks that need to inspect the output.
+func (r *checkRun) capture(step Step) []string {
+	var lines []string
+	r.runStep(step, false, &lines)
+	return lines
+}
+
+// parallel runs independent steps concurrently, tagging every output
+// line with the step label so interleaved output stays attributable.
+func (r *checkRun) parallel(steps ...Step) {
+	var wg sync.WaitGroup
+	for _, step := range steps {
+		wg.Add(1)
+		go func(step Step) {
+			defer wg.Done()
+			r.runStep(step, true, nil)
+		}(step)
+	}
+	wg.Wait()
+}
+
+// runStep runs a single command, streaming its output as run events.
+// Labelled steps carry their label on every event. If stdoutLines is set,
+// stdout is also collected into it.
+func (r *checkRun) runStep(step Step, labelled bool, stdoutLines *[]string) {
+	if r.stopped() {
+		return
+	}
+	r.header("Status : Running...")
+
+	r.mu.Lock()
+	r.nextStep++
+	res := StepResult{Index: r.nextStep, Label: step.Label, Command: step.Command, Args: step.Args, Start: time.Now(), ExitCode: -1}
+	r.mu.Unlock()
+
+	// Record the step however it ends
+	defer func() {
+		res.End = time.Now()
+		res.DurationMs = res.End.Sub(res.Start).Milliseconds()
+		r.mu.Lock()
+		r.stepResults = append(r.stepResults, res)
+		r.mu.Unlock()
+	}()
+
+	label := ""
+	if labelled {
+		label = step.Label
+	}
+	emitLine := func(stream, level, text string) {
+		r.emit(RunEvent{Step: res.Index, StepLabel: label, Stream: stream, Level: level, Text: text})
+	}
+	stepFailed := func(err error, msg string) {
+		res.Error = err.Error()
+		r.mu.Lock()
+		r.failedSteps++
+		r.mu.Unlock()
+		emitLine(streamSystem, levelError, msg)
+	}
+
+	// The context kills the whole process tree on cancellation or timeout
+	cmd := exec.CommandContext(r.ctx, step.Command, step.Args...)
+	cmd.SysProcAttr = getSysProcAttr()
+	cmd.Cancel = func() error { return killProcessTree(cmd) }
+	cmd.WaitDelay = 2 * time.Second
+
+	// Setup pipes. io.Pipe (rather than StdoutPipe) makes Wait return only
+	// after all output has been copied, so no trailing lines are lost.
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+
+	if err := cmd.Start(); err != nil {
+		stepFailed(err, fmt.Sprintf("[ERROR] Failed to start command: %s", err))
+		return
+	}
+
+	// Read logs concurrently
+	doneReading := make(chan bool)
+
+	// Stdout reader
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			res.StdoutLines++
+			if stdoutLines != nil {
+				*stdoutLines = append(*stdoutLines, scanner.Text())
+			}
+			if step.OnStdout != nil {
+				step.OnStdout(scanner.Text())
+			}
+			if !step.Quiet {
+				emitLine(streamStdout, levelInfo, scanner.Text())
+			}
+		}
+		io.Copy(io.Discard, stdout) // Keep draining if a line was too long
+		doneReading <- true
+	}()
+
+	// Stderr reader
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			res.StderrLines++
+			emitLine(streamStderr, levelError, scanner.Text())
+		}
+		io.Copy(io.Discard, stderr)
+		doneReading <- true
+	}()
+
+	// Wait for process
+	err := cmd.Wait()
+	stdoutW.Close()
+	stderrW.Close()
+
+	// Wait for readers to drain
+	<-doneReading
+	<-doneReading
+
+	res.ExitCode = cmd.ProcessState.ExitCode()
+	if err != nil && step.EmptyExitOK && res.ExitCode == 1 && res.StdoutLines == 0 && res.StderrLines == 0 {
+		err = nil
+	}
+
+	// Cancellation and timeouts are reported once for the whole run
+	if err != nil && r.stopped() {
+		res.Error = context.Cause(r.ctx).Error()
+	} else if err != nil {
+		stepFailed(err, fmt.Sprintf("[STOP] Process finished with error: %v", err))
+	}
+}
+
+// performFullCleanup runs the platform cleanup routine under the check
+// header. The routine runs its commands as steps of the run and checks
+// r.stopped() between sections, so cancel and the timeout stop it.
+func (r *checkRun) performFullCleanup(cleanup CheckFunc) {
+	r.header("Status : Running...")
+	cleanup(r)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// servicesFileName is stored next to the executable (see getAppBaseDir).
+// Its entries extend or replace the built-in knowledge base.
+const servicesFileName = "checkpoint-services.json"
+
+// Risk levels of a known service.
+const (
+	riskHigh   = "high"
+	riskMedium = "medium"
+	riskLow    = "low"
+)
+
+//go:embed services.json
+var builtinServicesJSON []byte
+
+// ServiceInfo describes a well-known service and why it matters when it is
+// reachable on a candidate machine.
+type ServiceInfo struct {
+	Port        int    `json:"port"`
+	Proto       string `json:"proto,omitempty"` // tcp (default) or udp
+	Name        string `json:"name"`
+	Risk        string `json:"risk"` // high, medium or low
+	Why         string `json:"why,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// servicesFile is the layout of services.json and checkpoint-services.json.
+type servicesFile struct {
+	Services []ServiceInfo `json:"services"`
+}
+
+// serviceKey identifies a service by protocol and port.
+type serviceKey struct {
+	proto string
+	port  int
+}
+
+// serviceKB maps ports to the services known to run there.
+type serviceKB map[serviceKey]ServiceInfo
+
+// builtinServices is the knowledge base shipped with the application.
+var builtinServices = mustParseServices(builtinServicesJSON)
+
+// mustParseServices parses the embedded knowledge base.
+func mustParseServices(data []byte) serviceKB {
+	var file servicesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		panic(fmt.Sprintf("services.json: %v", err))
+	}
+	kb := serviceKB{}
+	for _, svc := range file.Services {
+		if err := svc.validate(); err != nil {
+			panic(fmt.Sprintf("services.json: port %d: %v", svc.Port, err))
+		}
+		kb.add(svc)
+	}
+	return kb
+}
+
+// validate checks a knowledge base entry.
+func (svc ServiceInfo) validate() error {
+	if svc.Port < 1 || svc.Port > 65535 {
+		return errors.New("port must be between 1 and 65535")
+	}
+	if svc.Proto != "" && svc.Proto != "tcp" && svc.Proto != "udp" {
+		return fmt.Errorf("invalid proto %q (use tcp or udp)", svc.Proto)
+	}
+	if svc.Name == "" {
+		return errors.New("name is required")
+	}
+	switch svc.Risk {
+	case riskHigh, riskMedium, riskLow:
+	default:
+		return fmt.Errorf("invalid risk %q (use high, medium or low)", svc.Risk)
+	}
+	return nil
+}
+
+// add stores svc, replacing any entry for the same protocol and port.
+func (kb serviceKB) add(svc ServiceInfo) {
+	if svc.Proto == "" {
+		svc.Proto = "tcp"
+	}
+	kb[serviceKey{svc.Proto, svc.Port}] = svc
+}
+
+// lookup returns the service known on proto/port.
+func (kb serviceKB) lookup(proto string, port int) (ServiceInfo, bool) {
+	svc, ok := kb[serviceKey{proto, port}]
+	return svc, ok
+}
+
+// finding turns an exposed service into a finding: high risk is an error,
+// medium risk a warning and low risk nothing. subject names what was
+// found, e.g. "Port 3389/tcp is open".
+func (svc ServiceInfo) finding(subject string) (Finding, bool) {
+	level := levelWarn
+	switch svc.Risk {
+	case riskHigh:
+		level = levelError
+	case riskLow:
+		return Finding{}, false
+	}
+	msg := fmt.Sprintf("%s: %s, %s risk.", subject, svc.Name, svc.Risk)
+	if svc.Why != "" {
+		msg += " " + svc.Why
+	}
+	if svc.Remediation != "" {
+		msg += " Fix: " + svc.Remediation
+	}
+	return Finding{Level: level, Message: msg}, true
+}
+
+// loadServices builds the knowledge base from the built-in entries and the
+// services file. Invalid entries are skipped and reported in the returned
+// warnings.
+func (a *App) loadServices() (warnings []string) {
+	baseDir, err := a.getAppBaseDir()
+	if err != nil {
+		a.services = builtinServices
+		return []string{fmt.Sprintf("[WARN] Services file not loaded: %s", err)}
+	}
+	a.services, warnings = loadServicesFile(baseDir)
+	return warnings
+}
+
+// loadServicesFile merges the services file in baseDir into a copy of the
+// built-in knowledge base.
+func loadServicesFile(baseDir string) (serviceKB, []string) {
+	kb := serviceKB{}
+	for k, svc := range builtinServices {
+		kb[k] = svc
+	}
+
+	data, err := os.ReadFile(filepath.Join(baseDir, servicesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return kb, nil
+	}
+	if err != nil {
+		return kb, []string{fmt.Sprintf("[WARN] Services file not loaded: %s", err)}
+	}
+
+	var file servicesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return kb, []string{fmt.Sprintf("[WARN] Invalid %s: %s", servicesFileName, err)}
+	}
+	var warnings []string
+	for _, svc := range file.Services {
+		if err := svc.validate(); err != nil {
+			warnings = append(warnings, fmt.Sprintf("[WARN] %s: skipping port %d: %s", servicesFileName, svc.Port, err))
+			continue
+		}
+		kb.add(svc)
+	}
+	return kb, warnings
+}
+
+// knownServices returns the loaded knowledge base, or the built-in one
+// before startup has loaded it.
+func (a *App) knownServices() serviceKB {
+	if a.services == nil {
+		return builtinServices
+	}
+	return a.services
+}
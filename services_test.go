@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinServices(t *testing.T) {
+	var file servicesFile
+	dec := json.NewDecoder(strings.NewReader(string(builtinServicesJSON)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		t.Fatalf("services.json: %v", err)
+	}
+	if len(file.Services) == 0 {
+		t.Fatal("services.json lists no services")
+	}
+	// Duplicates would silently replace each other in the knowledge base
+	if kb := mustParseServices(builtinServicesJSON); len(kb) != len(file.Services) {
+		t.Errorf("services.json has %d entries but %d distinct proto/port pairs", len(file.Services), len(kb))
+	}
+	for _, svc := range file.Services {
+		if svc.Why == "" || svc.Remediation == "" {
+			t.Errorf("services.json: port %d (%s) lacks why or remediation", svc.Port, svc.Name)
+		}
+	}
+	// Ports the firewall and port checks single out must be known
+	for _, port := range remoteAccessPorts {
+		if _, ok := builtinServices.lookup("tcp", port); !ok {
+			t.Errorf("services.json has no entry for %d/tcp", port)
+		}
+	}
+}
+
+func TestMustParseServicesRejectsInvalidEntries(t *testing.T) {
+	for _, data := range []string{
+		`{"services": [`,
+		`{"services": [{"port": 0, "name": "Zero", "risk": "low"}]}`,
+		`{"services": [{"port": 22, "name": "SSH", "risk": "severe"}]}`,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("mustParseServices(%s) did not panic", data)
+				}
+			}()
+			mustParseServices([]byte(data))
+		}()
+	}
+}
+
+func TestLoadServicesFile(t *testing.T) {
+	// No services file: the built-in knowledge base
+	kb, warnings := loadServicesFile(t.TempDir())
+	if len(warnings) != 0 || len(kb) != len(builtinServices) {
+		t.Errorf("without a services file: %d services, warnings %q; want %d, none", len(kb), warnings, len(builtinServices))
+	}
+
+	dir := t.TempDir()
+	overrides := `{"services": [
+		{"port": 22, "name": "SSH (exam jump host)", "risk": "low"},
+		{"port": 5938, "name": "TeamViewer", "risk": "high", "why": "Remote control."},
+		{"port": 5353, "proto": "udp", "name": "mDNS", "risk": "low"},
+		{"port": 70000, "name": "Out of range", "risk": "high"},
+		{"port": 8080, "proto": "sctp", "name": "Odd", "risk": "low"}
+	]}`
+	if err := os.WriteFile(filepath.Join(dir, servicesFileName), []byte(overrides), 0644); err != nil {
+		t.Fatal(err)
+	}
+	kb, warnings = loadServicesFile(dir)
+
+	if svc, _ := kb.lookup("tcp", 22); svc.Name != "SSH (exam jump host)" || svc.Risk != riskLow {
+		t.Errorf("22/tcp = %+v, want the override", svc)
+	}
+	if svc, ok := kb.lookup("tcp", 5938); !ok || svc.Risk != riskHigh {
+		t.Errorf("5938/tcp = %+v, %v; want the added service", svc, ok)
+	}
+	if _, ok := kb.lookup("udp", 5353); !ok {
+		t.Error("5353/udp missing")
+	}
+	if _, ok := kb.lookup("tcp", 5353); ok {
+		t.Error("5353/udp also stored as tcp")
+	}
+	if svc, _ := builtinServices.lookup("tcp", 22); svc.Name != "SSH" {
+		t.Errorf("the override changed the built-in entry: %+v", svc)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "skipping port 70000") || !strings.Contains(warnings[1], `invalid proto "sctp"`) {
+		t.Errorf("warnings = %q, want the two invalid entries", warnings)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, servicesFileName), []byte(`{"services": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if kb, warnings = loadServicesFile(dir); len(warnings) != 1 || !strings.HasPrefix(warnings[0], "[WARN] Invalid checkpoint-services.json") || len(kb) != len(builtinServices) {
+		t.Errorf("invalid file: %d services, warnings %q; want the built-in ones and one warning", len(kb), warnings)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// settingsFileName is stored next to the executable (see getAppBaseDir).
+const settingsFileName = "checkpoint-settings.json"
+
+// defaultCheckTimeout applies to checks that do not define their own timeout.
+const defaultCheckTimeout = 2 * time.Minute
+
+// Settings holds user-editable configuration persisted in the portable directory.
+type Settings struct {
+	// Timeouts overrides the per-check timeout, in seconds, keyed by check ID.
+	Timeouts map[string]int `json:"timeouts,omitempty"`
+
+	// MaxConcurrentRuns bounds how many checks execute at once (0 = default).
+	MaxConcurrentRuns int `json:"maxConcurrentRuns,omitempty"`
+
+	// Traceroute configures the Traceroute check.
+	Traceroute TracerouteSettings `json:"traceroute"`
+
+	// Connectivity configures the probes of the Ping Connectivity check.
+	Connectivity ConnectivitySettings `json:"connectivity"`
+
+	// PortScan configures the "Check active network service ports" check.
+	PortScan PortScanSettings `json:"portScan"`
+
+	// TLS configures the "Inspect TLS certificates" check.
+	TLS TLSSettings `json:"tls"`
+
+	// DNS configures the DNS Configuration check.
+	DNS DNSSettings `json:"dns"`
+
+	// Proxy configures the Proxy Configuration check.
+	Proxy ProxySettings `json:"proxy"`
+}
+
+// settingsStore guards the loaded settings.
+type settingsStore struct {
+	mu       sync.RWMutex
+	settings Settings
+}
+
+// settingsPath returns the location of the settings file.
+func (a *App) settingsPath() (string, error) {
+	baseDir, err := a.getAppBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, settingsFileName), nil
+}
+
+// loadSettings reads the settings file. A missing file is not an error.
+func (a *App) loadSettings() error {
+	path, err := a.settingsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid %s: %w", settingsFileName, err)
+	}
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("invalid %s: %w", settingsFileName, err)
+	}
+
+	a.settings.mu.Lock()
+	a.settings.settings = s
+	a.settings.mu.Unlock()
+	return nil
+}
+
+// validate checks the settings as a whole. Timeouts may only name
+// registered checks, so custom checks must be loaded first.
+func (s *Settings) validate() error {
+	for id, secs := range s.Timeouts {
+		if lookupCheck(id) == nil {
+			return fmt.Errorf("unknown check in timeouts: %s", id)
+		}
+		if secs <= 0 {
+			return fmt.Errorf("timeout for %s must be positive", id)
+		}
+	}
+
+	if s.MaxConcurrentRuns < 0 {
+		return fmt.Errorf("maxConcurrentRuns must not be negative")
+	}
+	if err := s.Traceroute.validate(); err != nil {
+		return fmt.Errorf("traceroute: %w", err)
+	}
+	if err := s.Connectivity.validate(); err != nil {
+		return fmt.Errorf("connectivity: %w", err)
+	}
+	if err := s.PortScan.validate(); err != nil {
+		return fmt.Errorf("portScan: %w", err)
+	}
+	if err := s.TLS.validate(); err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	if err := s.DNS.validate(); err != nil {
+		return fmt.Errorf("dns: %w", err)
+	}
+	if err := s.Proxy.validate(); err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+	return nil
+}
+
+// GetSettings returns the current settings
+func (a *App) GetSettings() Settings {
+	a.settings.mu.RLock()
+	defer a.settings.mu.RUnlock()
+	return a.settings.settings
+}
+
+// SaveSettings replaces the current settings and writes them to the settings file
+func (a *App) SaveSettings(s Settings) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	path, err := a.settingsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	a.settings.mu.Lock()
+	a.settings.settings = s
+	a.settings.mu.Unlock()
+	return nil
+}
+
+// timeoutFor returns the effective timeout for a check: the settings
+// override, then the check definition, then defaultCheckTimeout.
+func (a *App) timeoutFor(c *Check) time.Duration {
+	a.settings.mu.RLock()
+	secs := a.settings.settings.Timeouts[c.ID]
+	a.settings.mu.RUnlock()
+
+	if secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultCheckTimeout
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		err      string // Substring of the error; empty if valid
+	}{
+		{Settings{}, ""},
+		{Settings{Timeouts: map[string]int{"cek-ip": 30, "full-cleanup": 600}}, ""},
+		{Settings{Timeouts: map[string]int{"no-such-check": 30}}, "unknown check in timeouts: no-such-check"},
+		{Settings{Timeouts: map[string]int{"cek-ip": 0}}, "timeout for cek-ip must be positive"},
+		{Settings{Timeouts: map[string]int{"cek-ip": -5}}, "timeout for cek-ip must be positive"},
+		{Settings{MaxConcurrentRuns: -1}, "maxConcurrentRuns"},
+	}
+	for _, tt := range tests {
+		err := tt.settings.validate()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%+v: unexpected error %v", tt.settings, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%+v: got error %v, want %q", tt.settings, err, tt.err)
+		}
+	}
+}
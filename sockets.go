@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Socket filters of the socket table checks.
+const (
+	socketsAll         = "all"
+	socketsListening   = "listening"
+	socketsEstablished = "established"
+)
+
+// Socket is one entry of the socket table.
+type Socket struct {
+	Proto         string `json:"proto"`  // tcp or udp
+	Family        string `json:"family"` // ipv4 or ipv6
+	LocalAddr     string `json:"localAddr"`
+	LocalPort     int    `json:"localPort"`
+	RemoteAddr    string `json:"remoteAddr,omitempty"`
+	RemotePort    int    `json:"remotePort,omitempty"`
+	State         string `json:"state,omitempty"` // LISTEN, ESTABLISHED, ...; empty for unconnected UDP
+	PID           int    `json:"pid,omitempty"`   // 0 if the owner is not visible to this user
+	Process       string `json:"process,omitempty"`
+	AllInterfaces bool   `json:"allInterfaces,omitempty"` // Listening on the wildcard address
+	Service       string `json:"service,omitempty"`       // Listening sockets: from the service knowledge base
+	Risk          string `json:"risk,omitempty"`
+	inode         string // Linux only: joins /proc/net entries to /proc/<pid>/fd
+}
+
+// listening reports whether the socket accepts connections (TCP) or
+// datagrams from anyone (unconnected UDP).
+func (s Socket) listening() bool {
+	return s.State == "LISTEN" || (s.Proto == "udp" && s.State == "")
+}
+
+// matches reports whether the socket passes the filter.
+func (s Socket) matches(filter string) bool {
+	switch filter {
+	case socketsListening:
+		return s.listening()
+	case socketsEstablished:
+		return s.State == "ESTABLISHED"
+	}
+	return true
+}
+
+// SocketTable is the structured output of the socket table checks.
+type SocketTable struct {
+	Filter   string    `json:"filter"`
+	Sockets  []Socket  `json:"sockets"`
+	Findings []Finding `json:"findings"` // Sockets listening on all interfaces
+}
+
+// checkSockets returns a check that lists the sockets passing filter (see
+// readSockets) and highlights those listening on all interfaces. Known
+// services raise the finding to their risk level.
+func checkSockets(filter string) CheckFunc {
+	return func(r *checkRun) {
+		r.header("Status : Reading sockets...", "Filter : "+filter)
+		all, err := readSockets(r)
+		if r.stopped() {
+			return
+		}
+		if err != nil {
+			r.fail(fmt.Sprintf("Cannot read socket table: %v", err))
+			return
+		}
+
+		kb := r.app.knownServices()
+		table := SocketTable{Filter: filter, Sockets: []Socket{}, Findings: []Finding{}}
+		for _, s := range all {
+			if !s.matches(filter) {
+				continue
+			}
+			if s.listening() {
+				s.AllInterfaces = isWildcard(s.LocalAddr)
+				if svc, ok := kb.lookup(s.Proto, s.LocalPort); ok {
+					s.Service, s.Risk = svc.Name, svc.Risk
+				}
+			}
+			table.Sockets = append(table.Sockets, s)
+		}
+		sortSockets(table.Sockets)
+
+		for _, s := range table.Sockets {
+			if !s.AllInterfaces {
+				continue
+			}
+			subject := fmt.Sprintf("Listening on all interfaces: %s %s (%s)", strings.ToUpper(s.Proto), endpoint(s.LocalAddr, s.LocalPort), owner(s))
+			f := Finding{Level: levelWarn, Message: subject}
+			if svc, ok := kb.lookup(s.Proto, s.LocalPort); ok {
+				if sf, ok := svc.finding(subject); ok {
+					f = sf
+				} else {
+					f.Message = fmt.Sprintf("%s: %s, %s risk.", subject, svc.Name, svc.Risk)
+				}
+			}
+			table.Findings = append(table.Findings, f)
+		}
+
+		r.setData("sockets", table)
+		r.log(formatSocketTable(table.Sockets))
+		if len(table.Findings) == 0 && filter != socketsEstablished {
+			r.log("[OK] No sockets listening on all interfaces.")
+		}
+		r.report(table.Findings)
+	}
+}
+
+// sortSockets orders sockets by protocol, family, local port and remote end.
+func sortSockets(sockets []Socket) {
+	sort.SliceStable(sockets, func(i, j int) bool {
+		a, b := sockets[i], sockets[j]
+		if a.Proto != b.Proto {
+			return a.Proto < b.Proto
+		}
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		if a.LocalPort != b.LocalPort {
+			return a.LocalPort < b.LocalPort
+		}
+		if a.RemoteAddr != b.RemoteAddr {
+			return a.RemoteAddr < b.RemoteAddr
+		}
+		return a.RemotePort < b.RemotePort
+	})
+}
+
+// formatSocketTable renders sockets as an aligned text table. Sockets
+// listening on all interfaces are marked with "!".
+func formatSocketTable(sockets []Socket) string {
+	if len(sockets) == 0 {
+		return "No matching sockets."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, " \tPROTO\tLOCAL\tREMOTE\tSTATE\tPID\tPROCESS\tSERVICE")
+	for _, s := range sockets {
+		mark, proto, state, pid, process, service := " ", s.Proto, s.State, "-", s.Process, "-"
+		if s.AllInterfaces {
+			mark = "!"
+		}
+		if s.Family == familyIPv6 {
+			proto += "6"
+		}
+		if state == "" {
+			state = "-"
+		}
+		if s.PID > 0 {
+			pid = strconv.Itoa(s.PID)
+		}
+		if process == "" {
+			process = "-"
+		}
+		if s.Service != "" {
+			service = fmt.Sprintf("%s (%s risk)", s.Service, s.Risk)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", mark, proto, endpoint(s.LocalAddr, s.LocalPort), endpoint(s.RemoteAddr, s.RemotePort), state, pid, process, service)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// endpoint formats an address and port; unset ends print as "*:*".
+func endpoint(addr string, port int) string {
+	if port == 0 && (addr == "" || isWildcard(addr)) {
+		return "*:*"
+	}
+	p := "*"
+	if port > 0 {
+		p = strconv.Itoa(port)
+	}
+	if addr == "" {
+		addr = "*"
+	}
+	return net.JoinHostPort(addr, p)
+}
+
+// owner describes the process owning a socket.
+func owner(s Socket) string {
+	switch {
+	case s.PID > 0 && s.Process != "":
+		return fmt.Sprintf("%s, PID %d", s.Process, s.PID)
+	case s.PID > 0:
+		return fmt.Sprintf("PID %d", s.PID)
+	}
+	return "owner unknown"
+}
+
+// isWildcard reports whether addr is the unspecified address.
+func isWildcard(addr string) bool {
+	if addr == "*" {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// tcpStates maps the state codes of /proc/net/tcp to their names.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// parseProcSockets parses /proc/net/{tcp,tcp6,udp,udp6}. The inode of each
+// socket is kept so it can be joined to the owning process.
+func parseProcSockets(data, proto, family string) []Socket {
+	var sockets []Socket
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] == "sl" {
+			continue
+		}
+		localIP, localPort := parseProcEndpoint(fields[1])
+		remoteIP, remotePort := parseProcEndpoint(fields[2])
+		if localIP == nil || remoteIP == nil {
+			continue
+		}
+
+		s := Socket{
+			Proto:      proto,
+			Family:     family,
+			LocalAddr:  localIP.String(),
+			LocalPort:  localPort,
+			RemoteAddr: remoteIP.String(),
+			RemotePort: remotePort,
+			inode:      fields[9],
+		}
+		if proto == "tcp" {
+			s.State = tcpStates[fields[3]]
+		} else if fields[3] == "01" {
+			s.State = "ESTABLISHED" // Connected UDP socket
+		}
+		if remotePort == 0 && remoteIP.IsUnspecified() {
+			s.RemoteAddr = ""
+		}
+		sockets = append(sockets, s)
+	}
+	return sockets
+}
+
+// parseProcEndpoint decodes an "ADDR:PORT" pair of /proc/net/tcp. IPv6
+// addresses are four 32-bit words, each in host (little-endian) order.
+func parseProcEndpoint(s string) (net.IP, int) {
+	addr, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0
+	}
+	if len(addr) == 8 {
+		return parseHexIPv4(addr), int(port)
+	}
+	ip := parseHexIPv6(addr)
+	if ip == nil {
+		return nil, 0
+	}
+	for i := 0; i < net.IPv6len; i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = ip[i+3], ip[i+2], ip[i+1], ip[i]
+	}
+	if v4 := ip.To4(); v4 != nil && !ip.IsUnspecified() {
+		return v4, int(port) // IPv4-mapped
+	}
+	return ip, int(port)
+}
+
+// windowsStates maps the states printed by Windows netstat to the names
+// used on Linux.
+var windowsStates = map[string]string{
+	"LISTENING":    "LISTEN",
+	"SYN_RECEIVED": "SYN_RECV",
+	"FIN_WAIT_1":   "FIN_WAIT1",
+	"FIN_WAIT_2":   "FIN_WAIT2",
+	"CLOSED":       "CLOSE",
+}
+
+// parseNetstatSockets parses the output of `netstat -ano` on Windows. UDP
+// rows have no state column.
+func parseNetstatSockets(out string) []Socket {
+	var sockets []Socket
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		proto := strings.ToLower(fields[0])
+		if proto != "tcp" && proto != "udp" {
+			continue
+		}
+		state := ""
+		if proto == "tcp" && len(fields) >= 5 {
+			state = fields[3]
+			if s, ok := windowsStates[state]; ok {
+				state = s
+			}
+		}
+		pid, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			continue
+		}
+
+		localAddr, localPort := splitEndpoint(fields[1])
+		remoteAddr, remotePort := splitEndpoint(fields[2])
+		sockets = append(sockets, Socket{
+			Proto:      proto,
+			Family:     endpointFamily(localAddr),
+			LocalAddr:  localAddr,
+			LocalPort:  localPort,
+			RemoteAddr: remoteAddr,
+			RemotePort: remotePort,
+			State:      state,
+			PID:        pid,
+		})
+	}
+	return sockets
+}
+
+// parseTasklist maps PIDs to image names from `tasklist /fo csv /nh`.
+func parseTasklist(out string) map[int]string {
+	names := make(map[int]string)
+	reader := csv.NewReader(strings.NewReader(out))
+	reader.FieldsPerRecord = -1
+	records, _ := reader.ReadAll()
+	for _, rec := range records {
+		if len(rec) < 2 {
+			continue
+		}
+		if pid, err := strconv.Atoi(rec[1]); err == nil {
+			names[pid] = rec[0]
+		}
+	}
+	return names
+}
+
+// parseLsofSockets parses `lsof -nP -i -F pcPnTt` (macOS). Each file starts
+// with an "f" line and belongs to the preceding "p"/"c" process lines.
+func parseLsofSockets(out string) []Socket {
+	var sockets []Socket
+	var pid int
+	var process string
+	var cur *Socket
+
+	flush := func() {
+		if cur != nil && (cur.Proto == "tcp" || cur.Proto == "udp") && cur.LocalAddr != "" {
+			if cur.Proto == "udp" && cur.RemoteAddr != "" {
+				cur.State = "ESTABLISHED" // Connected UDP socket
+			}
+			sockets = append(sockets, *cur)
+		}
+		cur = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			flush()
+			pid, _ = strconv.Atoi(value)
+			process = ""
+		case 'c':
+			process = value
+		case 'f':
+			flush()
+			cur = &Socket{PID: pid, Process: process}
+		case 't':
+			if cur != nil {
+				cur.Family = familyIPv4
+				if value == "IPv6" {
+					cur.Family = familyIPv6
+				}
+			}
+		case 'P':
+			if cur != nil {
+				cur.Proto = strings.ToLower(value)
+			}
+		case 'n':
+			if cur != nil {
+				local, remote, _ := strings.Cut(value, "->")
+				cur.LocalAddr, cur.LocalPort = splitEndpoint(local)
+				if remote != "" {
+					cur.RemoteAddr, cur.RemotePort = splitEndpoint(remote)
+				}
+			}
+		case 'T':
+			if cur != nil && strings.HasPrefix(value, "ST=") {
+				cur.State = strings.TrimPrefix(value, "ST=")
+			}
+		}
+	}
+	flush()
+	return sockets
+}
+
+// splitEndpoint splits "addr:port", "[v6addr]:port" or "*:*" as printed by
+// netstat and lsof. Unset ends ("*:*", "0.0.0.0:0") become empty and
+// zones are dropped.
+func splitEndpoint(s string) (string, int) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0
+	}
+	addr := stripZone(strings.Trim(s[:i], "[]"))
+	port, _ := strconv.Atoi(s[i+1:])
+	if port == 0 && isWildcard(addr) {
+		return "", 0
+	}
+	return addr, port
+}
+
+// endpointFamily guesses the family of an address printed by netstat.
+func endpointFamily(addr string) string {
+	if strings.Contains(addr, ":") {
+		return familyIPv6
+	}
+	return familyIPv4
+}
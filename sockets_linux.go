@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procSocketFiles lists the /proc/net tables read by readSockets.
+var procSocketFiles = []struct{ name, proto, family string }{
+	{"tcp", "tcp", familyIPv4},
+	{"tcp6", "tcp", familyIPv6},
+	{"udp", "udp", familyIPv4},
+	{"udp6", "udp", familyIPv6},
+}
+
+// readSockets reads the socket tables from /proc/net and finds the owning
+// processes through /proc/<pid>/fd. Processes of other users are only
+// visible when running as root.
+func readSockets(r *checkRun) ([]Socket, error) {
+	var sockets []Socket
+	for _, f := range procSocketFiles {
+		data, err := os.ReadFile(filepath.Join("/proc/net", f.name))
+		if err != nil {
+			if f.family == familyIPv6 {
+				continue // IPv6 may be disabled
+			}
+			return nil, err
+		}
+		sockets = append(sockets, parseProcSockets(string(data), f.proto, f.family)...)
+	}
+
+	owners := socketOwners()
+	for i := range sockets {
+		if pid, ok := owners[sockets[i].inode]; ok {
+			sockets[i].PID = pid
+			sockets[i].Process = readTrimmed(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+		}
+	}
+	return sockets, nil
+}
+
+// socketOwners maps socket inodes to the PID holding them open.
+func socketOwners() map[string]int {
+	owners := make(map[string]int)
+	procs, _ := os.ReadDir("/proc")
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err == nil && strings.HasPrefix(link, "socket:[") {
+				owners[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] = pid
+			}
+		}
+	}
+	return owners
+}
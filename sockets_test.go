@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSocketParsers(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(t *testing.T) []Socket
+		want  []Socket
+	}{
+		{"proc tcp", func(t *testing.T) []Socket {
+			return parseProcSockets(readFixture(t, "proc_net_tcp.txt"), "tcp", familyIPv4)
+		}, []Socket{
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "0.0.0.0", LocalPort: 22, State: "LISTEN", inode: "24315"},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "127.0.0.1", LocalPort: 631, State: "LISTEN", inode: "31877"},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "192.168.1.23", LocalPort: 51514, RemoteAddr: "140.82.112.3", RemotePort: 443, State: "ESTABLISHED", inode: "95021"},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "127.0.0.1", LocalPort: 37251, RemoteAddr: "127.0.0.1", RemotePort: 8080, State: "TIME_WAIT", inode: "0"},
+		}},
+		{"proc tcp6", func(t *testing.T) []Socket {
+			return parseProcSockets(readFixture(t, "proc_net_tcp6.txt"), "tcp", familyIPv6)
+		}, []Socket{
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "::", LocalPort: 22, State: "LISTEN", inode: "24317"},
+			// IPv4-mapped addresses are shown as IPv4
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "192.168.1.23", LocalPort: 8080, RemoteAddr: "192.168.1.2", RemotePort: 54321, State: "ESTABLISHED", inode: "95530"},
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "2001:db8::23", LocalPort: 57842, RemoteAddr: "2001:db8::1", RemotePort: 443, State: "ESTABLISHED", inode: "95611"},
+		}},
+		{"proc udp", func(t *testing.T) []Socket {
+			return parseProcSockets(readFixture(t, "proc_net_udp.txt"), "udp", familyIPv4)
+		}, []Socket{
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "127.0.0.53", LocalPort: 53, inode: "20781"},
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "0.0.0.0", LocalPort: 5353, inode: "21533"},
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "192.168.1.23", LocalPort: 53548, RemoteAddr: "192.168.1.1", RemotePort: 53, State: "ESTABLISHED", inode: "96102"},
+		}},
+		{"netstat -ano", func(t *testing.T) []Socket {
+			return parseNetstatSockets(readFixture(t, "netstat_ano_windows.txt"))
+		}, []Socket{
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "0.0.0.0", LocalPort: 135, State: "LISTEN", PID: 1012},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "0.0.0.0", LocalPort: 3389, State: "LISTEN", PID: 1240},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "127.0.0.1", LocalPort: 9012, State: "LISTEN", PID: 4},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "192.168.1.50", LocalPort: 50312, RemoteAddr: "20.42.73.29", RemotePort: 443, State: "ESTABLISHED", PID: 7312},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "192.168.1.50", LocalPort: 50320, RemoteAddr: "52.113.194.132", RemotePort: 443, State: "TIME_WAIT"},
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "::", LocalPort: 135, State: "LISTEN", PID: 1012},
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "fe80::1c3a:5e7f:9b2d:4c61", LocalPort: 50400, RemoteAddr: "fe80::a691:b1ff:fe2c:3d4e", RemotePort: 445, State: "SYN_SENT", PID: 4},
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "0.0.0.0", LocalPort: 5353, PID: 2284},
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "127.0.0.1", LocalPort: 1900, PID: 3920},
+			{Proto: "udp", Family: familyIPv6, LocalAddr: "::", LocalPort: 5353, PID: 2284},
+		}},
+		{"lsof", func(t *testing.T) []Socket {
+			return parseLsofSockets(readFixture(t, "lsof_darwin.txt"))
+		}, []Socket{
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "*", LocalPort: 5353, PID: 412, Process: "mDNSResponder"},
+			{Proto: "udp", Family: familyIPv6, LocalAddr: "*", LocalPort: 5353, PID: 412, Process: "mDNSResponder"},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "*", LocalPort: 49152, State: "LISTEN", PID: 611, Process: "rapportd"},
+			{Proto: "tcp", Family: familyIPv4, LocalAddr: "192.168.1.23", LocalPort: 51514, RemoteAddr: "140.82.112.3", RemotePort: 443, State: "ESTABLISHED", PID: 1288, Process: "Google Chrome He"},
+			{Proto: "tcp", Family: familyIPv6, LocalAddr: "2001:db8:1::23", LocalPort: 51620, RemoteAddr: "2001:db8::1", RemotePort: 443, State: "CLOSE_WAIT", PID: 1288, Process: "Google Chrome He"},
+			{Proto: "udp", Family: familyIPv4, LocalAddr: "192.168.1.23", LocalPort: 60512, RemoteAddr: "192.168.1.1", RemotePort: 53, State: "ESTABLISHED", PID: 1288, Process: "Google Chrome He"},
+		}},
+		{"lsof without sockets", func(t *testing.T) []Socket { return parseLsofSockets("") }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.parse(t)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %d sockets:\n%s\nwant %d:\n%s", len(got), formatSocketTable(got), len(tt.want), formatSocketTable(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseTasklist(t *testing.T) {
+	got := parseTasklist(readFixture(t, "tasklist_windows.txt"))
+	want := map[int]string{4: "System", 1012: "svchost.exe", 1240: "svchost.exe", 7312: "chrome.exe"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSocketFilters(t *testing.T) {
+	sockets := parseNetstatSockets(readFixture(t, "netstat_ano_windows.txt"))
+	count := func(filter string) (n, wildcard int) {
+		for _, s := range sockets {
+			if s.matches(filter) {
+				n++
+				if s.listening() && isWildcard(s.LocalAddr) {
+					wildcard++
+				}
+			}
+		}
+		return n, wildcard
+	}
+	tests := []struct {
+		filter      string
+		n, wildcard int
+	}{
+		{socketsAll, 10, 5},
+		{socketsListening, 7, 5},
+		{socketsEstablished, 1, 0},
+	}
+	for _, tt := range tests {
+		if n, wildcard := count(tt.filter); n != tt.n || wildcard != tt.wildcard {
+			t.Errorf("%s: got %d sockets (%d on all interfaces), want %d (%d)", tt.filter, n, wildcard, tt.n, tt.wildcard)
+		}
+	}
+}
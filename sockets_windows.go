@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// readSockets parses `netstat -ano` (numeric, so no slow reverse lookups)
+// and names the owning processes from tasklist.
+func readSockets(r *checkRun) ([]Socket, error) {
+	out := r.capture(Step{Command: "netstat", Args: []string{"-ano"}, Quiet: true})
+	sockets := parseNetstatSockets(strings.Join(out, "\n"))
+	if len(sockets) == 0 {
+		return nil, errors.New("no sockets in netstat output")
+	}
+
+	tasks := r.capture(Step{Command: "tasklist", Args: []string{"/fo", "csv", "/nh"}, Quiet: true})
+	names := parseTasklist(strings.Join(tasks, "\n"))
+	for i := range sockets {
+		sockets[i].Process = names[sockets[i].PID]
+	}
+	return sockets, nil
+}
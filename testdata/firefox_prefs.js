@@ -0,0 +1,12 @@
+// Mozilla User Preferences
+
+// DO NOT EDIT THIS FILE.
+user_pref("browser.startup.homepage", "https://moodle.school.example");
+user_pref("network.proxy.http", "127.0.0.1");
+user_pref("network.proxy.http_port", 8118);
+user_pref("network.proxy.socks", "127.0.0.1");
+user_pref("network.proxy.socks_port", 9050);
+user_pref("network.proxy.ssl", "127.0.0.1");
+user_pref("network.proxy.ssl_port", 8118);
+user_pref("network.proxy.type", 1);
+user_pref("network.trr.mode", 5);
@@ -0,0 +1,4 @@
+// Mozilla User Preferences
+user_pref("network.trr.custom_uri", "https://doh.example.net/dns-query");
+user_pref("network.trr.mode", 3);
+user_pref("network.trr.uri", "https://mozilla.cloudflare-dns.com/dns-query");
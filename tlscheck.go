@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS inspection defaults, used when the settings leave a field unset.
+const (
+	defaultTLSWarnDays = 30
+	defaultTLSTimeout  = 3 * time.Second
+)
+
+// TLSSettings configures the "Inspect TLS certificates" check. Zero values
+// select the defaults.
+type TLSSettings struct {
+	Targets   []string `json:"targets,omitempty"`   // host:port; empty to inspect every open port of the port scan
+	WarnDays  int      `json:"warnDays,omitempty"`  // Warn when a certificate expires within this many days, 1-365
+	TimeoutMs int      `json:"timeoutMs,omitempty"` // Per-handshake wait, 100-30000
+}
+
+// validate checks the settings ranges.
+func (s TLSSettings) validate() error {
+	for _, t := range s.Targets {
+		host, port, err := net.SplitHostPort(t)
+		if err != nil || !hostnamePattern.MatchString(host) {
+			return fmt.Errorf("target %q must be host:port", t)
+		}
+		if _, err := parsePort(port); err != nil {
+			return fmt.Errorf("target %q: %w", t, err)
+		}
+	}
+	if s.WarnDays < 0 || s.WarnDays > 365 {
+		return errors.New("warnDays must be between 1 and 365")
+	}
+	if s.TimeoutMs != 0 && (s.TimeoutMs < 100 || s.TimeoutMs > 30000) {
+		return errors.New("timeoutMs must be between 100 and 30000")
+	}
+	return nil
+}
+
+// withDefaults fills unset fields.
+func (s TLSSettings) withDefaults() TLSSettings {
+	if s.WarnDays == 0 {
+		s.WarnDays = defaultTLSWarnDays
+	}
+	if s.TimeoutMs == 0 {
+		s.TimeoutMs = int(defaultTLSTimeout / time.Millisecond)
+	}
+	return s
+}
+
+// CertInfo describes one certificate of a presented chain.
+type CertInfo struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SANs               []string  `json:"sans,omitempty"`
+	NotBefore          time.Time `json:"notBefore"`
+	NotAfter           time.Time `json:"notAfter"`
+	KeyType            string    `json:"keyType"` // RSA, ECDSA, Ed25519, DSA
+	KeyBits            int       `json:"keyBits"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	SelfSigned         bool      `json:"selfSigned"`
+	SHA256             string    `json:"sha256"` // Fingerprint
+}
+
+// TLSEndpoint is the result of one handshake.
+type TLSEndpoint struct {
+	Target      string     `json:"target"`
+	TLS         bool       `json:"tls"` // The port completed a TLS handshake
+	Error       string     `json:"error,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	CipherSuite string     `json:"cipherSuite,omitempty"`
+	Chain       []CertInfo `json:"chain,omitempty"` // Leaf first, as presented
+	VerifyError string     `json:"verifyError,omitempty"`
+	Findings    []Finding  `json:"findings,omitempty"`
+}
+
+// checkTLS implements "Inspect TLS certificates": it handshakes with the
+// configured targets, or every open TCP port of the port scan, and flags
+// expired, soon-to-expire, self-signed and weak-key certificates.
+func checkTLS(r *checkRun) {
+	cfg := r.app.GetSettings().TLS.withDefaults()
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+
+	targets := cfg.Targets
+	if len(targets) == 0 {
+		scan := r.app.GetSettings().PortScan.withDefaults()
+		r.header("Status : Scanning ports for TLS services...", "Target : "+scan.Target)
+		var err error
+		if targets, err = openTCPTargets(r.ctx, scan); err != nil {
+			r.fail(fmt.Sprintf("Cannot resolve %s: %v", scan.Target, err))
+			return
+		}
+	} else {
+		r.header("Status : Inspecting TLS certificates...", fmt.Sprintf("Targets : %d", len(targets)))
+	}
+	if r.stopped() {
+		return
+	}
+	if len(targets) == 0 {
+		r.log("[INFO] No open TCP ports to inspect.")
+		return
+	}
+
+	now := time.Now()
+	var endpoints []TLSEndpoint
+	var findings []Finding
+	for _, target := range targets {
+		if r.stopped() {
+			return
+		}
+		ep := inspectTLS(r.ctx, target, timeout, now, cfg.WarnDays)
+		endpoints = append(endpoints, ep)
+		if !ep.TLS {
+			r.log(fmt.Sprintf("[INFO] %s: no TLS (%s)", target, ep.Error))
+			continue
+		}
+		r.log(formatTLSEndpoint(ep, now))
+		findings = append(findings, ep.Findings...)
+	}
+	r.setData("tls", endpoints)
+
+	if len(findings) == 0 {
+		r.log("[OK] No certificate problems found.")
+	}
+	r.report(findings)
+}
+
+// openTCPTargets scans the port scan target and returns host:port for
+// every open TCP port.
+func openTCPTargets(ctx context.Context, cfg PortScanSettings) ([]string, error) {
+	ip, err := scanAddress(ctx, cfg.Target)
+	if err != nil {
+		return nil, err
+	}
+	ports, _ := parsePortList(cfg.Ports)
+	var targets []string
+	for _, res := range scanPorts(ctx, ip, ports, nil, time.Duration(cfg.TimeoutMs)*time.Millisecond, cfg.Workers) {
+		if res.State == portOpen {
+			targets = append(targets, net.JoinHostPort(ip.String(), strconv.Itoa(res.Port)))
+		}
+	}
+	return targets, nil
+}
+
+// inspectTLS handshakes with target without verifying the peer, so that
+// broken certificates can still be examined, then verifies the chain
+// against the system roots separately.
+func inspectTLS(ctx context.Context, target string, timeout time.Duration, now time.Time, warnDays int) TLSEndpoint {
+	ep := TLSEndpoint{Target: target}
+	host, _, _ := net.SplitHostPort(target)
+	serverName := host
+	if net.ParseIP(host) != nil {
+		serverName = ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", target)
+	if err != nil {
+		ep.Error = err.Error()
+		return ep
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	ep.TLS = true
+	ep.Version = tls.VersionName(state.Version)
+	ep.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	for _, cert := range state.PeerCertificates {
+		ep.Chain = append(ep.Chain, describeCert(cert))
+	}
+	if len(state.PeerCertificates) == 0 {
+		ep.Findings = []Finding{{Level: levelError, Message: target + ": no certificate presented."}}
+		return ep
+	}
+
+	verifyErr := verifyChain(state.PeerCertificates, serverName, now)
+	if verifyErr != nil {
+		ep.VerifyError = verifyErr.Error()
+	}
+	ep.Findings = analyzeCertificates(target, state.PeerCertificates, verifyErr, now, warnDays)
+	return ep
+}
+
+// verifyChain checks the presented chain against the system roots.
+func verifyChain(chain []*x509.Certificate, serverName string, now time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{DNSName: serverName, Intermediates: intermediates, CurrentTime: now})
+	return err
+}
+
+// describeCert extracts the recorded fields of a certificate.
+func describeCert(cert *x509.Certificate) CertInfo {
+	keyType, keyBits := publicKeyInfo(cert)
+	sum := sha256.Sum256(cert.Raw)
+	info := CertInfo{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SANs:               append([]string{}, cert.DNSNames...),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		KeyType:            keyType,
+		KeyBits:            keyBits,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SelfSigned:         selfSigned(cert),
+		SHA256:             hex.EncodeToString(sum[:]),
+	}
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	info.SANs = append(info.SANs, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		info.SANs = append(info.SANs, uri.String())
+	}
+	return info
+}
+
+// publicKeyInfo returns the key algorithm and size.
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return cert.PublicKeyAlgorithm.String(), 0 // DSA and unknown algorithms
+}
+
+// selfSigned reports whether the certificate is signed by its own key.
+func selfSigned(cert *x509.Certificate) bool {
+	// CheckSignatureFrom would insist on the CA flag, which self-signed
+	// leaf certificates usually lack
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// weakKey returns why a key is too weak, or "" if it is acceptable.
+func weakKey(keyType string, bits int) string {
+	switch {
+	case keyType == "DSA":
+		return "DSA keys are deprecated"
+	case keyType == "RSA" && bits < 2048:
+		return fmt.Sprintf("RSA key of %d bits (minimum 2048)", bits)
+	case keyType == "ECDSA" && bits < 256:
+		return fmt.Sprintf("ECDSA key of %d bits (minimum 256)", bits)
+	}
+	return ""
+}
+
+// analyzeCertificates flags problems with a presented chain: expired or
+// not yet valid certificates and weak keys are errors; certificates that
+// expire within warnDays, self-signed leaves, weak signatures and chains
+// the system does not trust are warnings.
+func analyzeCertificates(target string, chain []*x509.Certificate, verifyErr error, now time.Time, warnDays int) []Finding {
+	var findings []Finding
+	add := func(level, format string, args ...any) {
+		findings = append(findings, Finding{Level: level, Message: target + ": " + fmt.Sprintf(format, args...)})
+	}
+
+	for i, cert := range chain {
+		what := "certificate"
+		if i > 0 {
+			what = fmt.Sprintf("chain certificate %d", i)
+		}
+		name := certName(cert)
+		switch {
+		case now.After(cert.NotAfter):
+			add(levelError, "%s %s expired on %s.", what, name, cert.NotAfter.Format("2006-01-02"))
+		case now.Before(cert.NotBefore):
+			add(levelError, "%s %s is not valid before %s.", what, name, cert.NotBefore.Format("2006-01-02"))
+		case cert.NotAfter.Sub(now) < time.Duration(warnDays)*24*time.Hour:
+			add(levelWarn, "%s %s expires in %d day(s), on %s.", what, name, int(cert.NotAfter.Sub(now).Hours()/24), cert.NotAfter.Format("2006-01-02"))
+		}
+		keyType, bits := publicKeyInfo(cert)
+		if reason := weakKey(keyType, bits); reason != "" {
+			add(levelError, "%s %s has a weak key: %s.", what, name, reason)
+		}
+		switch cert.SignatureAlgorithm {
+		case x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+			if !selfSigned(cert) { // A root's self-signature is not relied upon
+				add(levelWarn, "%s %s is signed with %s.", what, name, cert.SignatureAlgorithm)
+			}
+		}
+	}
+
+	// Expiry is already reported above
+	var invalid x509.CertificateInvalidError
+	expired := errors.As(verifyErr, &invalid) && invalid.Reason == x509.Expired
+	if selfSigned(chain[0]) {
+		add(levelWarn, "certificate %s is self-signed.", certName(chain[0]))
+	} else if verifyErr != nil && !expired {
+		add(levelWarn, "certificate is not trusted by this system: %v", verifyErr)
+	}
+	return findings
+}
+
+// certName names a certificate by its common name, or its subject.
+func certName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return fmt.Sprintf("%q", cert.Subject.CommonName)
+	}
+	return fmt.Sprintf("%q", cert.Subject.String())
+}
+
+// formatTLSEndpoint renders a handshake result and its chain.
+func formatTLSEndpoint(ep TLSEndpoint, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s  %s", ep.Target, ep.Version, ep.CipherSuite)
+	for i, c := range ep.Chain {
+		fmt.Fprintf(&b, "\n  [%d] %s", i, c.Subject)
+		fmt.Fprintf(&b, "\n      Issuer : %s", c.Issuer)
+		if len(c.SANs) > 0 {
+			fmt.Fprintf(&b, "\n      SANs   : %s", strings.Join(c.SANs, ", "))
+		}
+		left := fmt.Sprintf("%d days left", int(c.NotAfter.Sub(now).Hours()/24))
+		if now.After(c.NotAfter) {
+			left = "expired"
+		}
+		fmt.Fprintf(&b, "\n      Valid  : %s .. %s (%s)", c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"), left)
+		fmt.Fprintf(&b, "\n      Key    : %s %d, %s", c.KeyType, c.KeyBits, c.SignatureAlgorithm)
+		if c.SelfSigned {
+			b.WriteString(", self-signed")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testKey returns a fresh key: an ECDSA key on curve, or an RSA key of
+// rsaBits if curve is nil.
+func testKey(t *testing.T, curve elliptic.Curve, rsaBits int) crypto.Signer {
+	t.Helper()
+	var key crypto.Signer
+	var err error
+	if curve != nil {
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	} else {
+		key, err = rsa.GenerateKey(rand.Reader, rsaBits)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// testCert issues a certificate for key named cn, valid from notBefore to
+// notAfter. It is signed by parent, or self-signed if parent is nil; a
+// certificate named "Test CA" is issued as a CA.
+func testCert(t *testing.T, cn string, key crypto.Signer, notBefore, notAfter time.Time, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	if cn == "Test CA" {
+		tmpl.IsCA = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		tmpl.DNSNames, tmpl.IPAddresses = nil, nil
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestWeakKey(t *testing.T) {
+	tests := []struct {
+		keyType string
+		bits    int
+		want    string
+	}{
+		{"RSA", 4096, ""},
+		{"RSA", 2048, ""},
+		{"RSA", 1024, "RSA key of 1024 bits (minimum 2048)"},
+		{"ECDSA", 256, ""},
+		{"ECDSA", 224, "ECDSA key of 224 bits (minimum 256)"},
+		{"Ed25519", 256, ""},
+		{"DSA", 0, "DSA keys are deprecated"},
+	}
+	for _, tt := range tests {
+		if got := weakKey(tt.keyType, tt.bits); got != tt.want {
+			t.Errorf("weakKey(%s, %d) = %q, want %q", tt.keyType, tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestSelfSigned(t *testing.T) {
+	caKey := testKey(t, elliptic.P256(), 0)
+	ca := testCert(t, "Test CA", caKey, testNow.AddDate(-1, 0, 0), testNow.AddDate(5, 0, 0), nil, nil)
+	leafKey := testKey(t, elliptic.P256(), 0)
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want bool
+	}{
+		{"root", ca, true},
+		{"self-signed leaf", testCert(t, "exam-server", leafKey, testNow, testNow.AddDate(1, 0, 0), nil, nil), true},
+		{"issued leaf", testCert(t, "exam-server", leafKey, testNow, testNow.AddDate(1, 0, 0), ca, caKey), false},
+		// Same subject as its issuer, but not signed by its own key
+		{"issuer's name", testCert(t, "Test CA", leafKey, testNow, testNow.AddDate(1, 0, 0), ca, caKey), false},
+	}
+	for _, tt := range tests {
+		if got := selfSigned(tt.cert); got != tt.want {
+			t.Errorf("%s: selfSigned = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeCertificates(t *testing.T) {
+	caKey := testKey(t, elliptic.P256(), 0)
+	ca := testCert(t, "Test CA", caKey, testNow.AddDate(-1, 0, 0), testNow.AddDate(5, 0, 0), nil, nil)
+	expiredCA := testCert(t, "Test CA", caKey, testNow.AddDate(-5, 0, 0), testNow.AddDate(0, 0, -1), nil, nil)
+	key := testKey(t, elliptic.P256(), 0)
+	year := testNow.AddDate(1, 0, 0)
+	expired := x509.CertificateInvalidError{Reason: x509.Expired}
+
+	tests := []struct {
+		name      string
+		chain     []*x509.Certificate
+		verifyErr error
+		want      []string
+	}{
+		{
+			name:  "trusted",
+			chain: []*x509.Certificate{testCert(t, "exam-server", key, testNow, year, ca, caKey), ca},
+		},
+		{
+			name:  "self-signed",
+			chain: []*x509.Certificate{testCert(t, "exam-server", key, testNow, year, nil, nil)},
+			want:  []string{`warn: exam.example:443: certificate "exam-server" is self-signed.`},
+		},
+		{
+			name:      "expired",
+			chain:     []*x509.Certificate{testCert(t, "exam-server", key, testNow.AddDate(-1, 0, 0), testNow.AddDate(0, 0, -3), ca, caKey), ca},
+			verifyErr: expired,
+			want:      []string{`error: certificate "exam-server" expired on 2026-02-26.`},
+		},
+		{
+			name:  "not yet valid",
+			chain: []*x509.Certificate{testCert(t, "exam-server", key, testNow.AddDate(0, 0, 2), year, ca, caKey), ca},
+			want:  []string{`error: certificate "exam-server" is not valid before 2026-03-03.`},
+		},
+		{
+			name:  "expiring soon",
+			chain: []*x509.Certificate{testCert(t, "exam-server", key, testNow.AddDate(0, -11, 0), testNow.Add(10*24*time.Hour+time.Hour), ca, caKey), ca},
+			want:  []string{`warn: certificate "exam-server" expires in 10 day(s), on 2026-03-11.`},
+		},
+		{
+			name:  "weak RSA key",
+			chain: []*x509.Certificate{testCert(t, "exam-server", testKey(t, nil, 1024), testNow, year, ca, caKey), ca},
+			want:  []string{`error: certificate "exam-server" has a weak key: RSA key of 1024 bits (minimum 2048).`},
+		},
+		{
+			name:  "weak ECDSA key",
+			chain: []*x509.Certificate{testCert(t, "exam-server", testKey(t, elliptic.P224(), 0), testNow, year, ca, caKey), ca},
+			want:  []string{`error: certificate "exam-server" has a weak key: ECDSA key of 224 bits (minimum 256).`},
+		},
+		{
+			name:      "expired intermediate",
+			chain:     []*x509.Certificate{testCert(t, "exam-server", key, testNow, year, expiredCA, caKey), expiredCA},
+			verifyErr: expired,
+			want:      []string{`error: chain certificate 1 "Test CA" expired on 2026-02-28.`},
+		},
+		{
+			name:      "untrusted",
+			chain:     []*x509.Certificate{testCert(t, "exam-server", key, testNow, year, ca, caKey)},
+			verifyErr: x509.UnknownAuthorityError{},
+			want:      []string{"warn: certificate is not trusted by this system: x509: certificate signed by unknown authority"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, analyzeCertificates("exam.example:443", tt.chain, tt.verifyErr, testNow, 30), tt.want)
+		})
+	}
+}
+
+// The chain is verified against the system roots, which never include the
+// test CA
+func TestVerifyChainUntrusted(t *testing.T) {
+	caKey := testKey(t, elliptic.P256(), 0)
+	ca := testCert(t, "Test CA", caKey, testNow.AddDate(-1, 0, 0), testNow.AddDate(5, 0, 0), nil, nil)
+	leaf := testCert(t, "exam-server", testKey(t, elliptic.P256(), 0), testNow, testNow.AddDate(1, 0, 0), ca, caKey)
+	if err := verifyChain([]*x509.Certificate{leaf, ca}, "localhost", testNow); err == nil {
+		t.Error("verifyChain accepted a chain from an unknown CA")
+	}
+}
+
+func TestInspectTLS(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	// inspectTLS hangs up after the handshake, which the server would log
+	quiet := log.New(io.Discard, "", 0)
+
+	t.Run("httptest server", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(handler)
+		srv.Config.ErrorLog = quiet
+		srv.StartTLS()
+		defer srv.Close()
+		ep := inspectTLS(context.Background(), srv.Listener.Addr().String(), 5*time.Second, time.Now(), 30)
+		if !ep.TLS || ep.Error != "" || ep.Version == "" || ep.CipherSuite == "" {
+			t.Fatalf("got %+v, want a completed handshake", ep)
+		}
+		if len(ep.Chain) != 1 || !ep.Chain[0].SelfSigned || ep.Chain[0].KeyType != "RSA" {
+			t.Errorf("chain = %+v, want one self-signed RSA certificate", ep.Chain)
+		}
+		assertFindings(t, ep.Findings, []string{"warn: is self-signed."})
+	})
+
+	t.Run("expired self-signed", func(t *testing.T) {
+		key := testKey(t, elliptic.P256(), 0)
+		cert := testCert(t, "exam-server", key, testNow.AddDate(-2, 0, 0), testNow.AddDate(-1, 0, 0), nil, nil)
+		srv := httptest.NewUnstartedServer(handler)
+		srv.Config.ErrorLog = quiet
+		srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}}
+		srv.StartTLS()
+		defer srv.Close()
+
+		ep := inspectTLS(context.Background(), srv.Listener.Addr().String(), 5*time.Second, testNow, 30)
+		if !ep.TLS || len(ep.Chain) != 1 || ep.Chain[0].KeyType != "ECDSA" || ep.Chain[0].KeyBits != 256 {
+			t.Fatalf("got %+v, want an ECDSA P-256 certificate", ep)
+		}
+		if !strings.Contains(ep.VerifyError, "expired") {
+			t.Errorf("verify error = %q, want expired", ep.VerifyError)
+		}
+		assertFindings(t, ep.Findings, []string{
+			`error: certificate "exam-server" expired on 2025-03-01.`,
+			`warn: certificate "exam-server" is self-signed.`,
+		})
+	})
+
+	t.Run("closed port", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := ln.Addr().String()
+		ln.Close()
+		ep := inspectTLS(context.Background(), addr, 5*time.Second, testNow, 30)
+		if ep.TLS || !strings.Contains(ep.Error, "refused") || ep.Findings != nil {
+			t.Errorf("got %+v, want connection refused", ep)
+		}
+	})
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Traceroute defaults, used when the settings leave a field unset.
+const (
+	defaultTracerouteTarget     = "8.8.8.8"
+	defaultTracerouteMaxHops    = 30
+	defaultTracerouteHopTimeout = 2 * time.Second
+	tracerouteProbes            = 3 // Probes per hop
+)
+
+// TracerouteSettings configures the Traceroute check. Zero values select
+// the defaults.
+type TracerouteSettings struct {
+	Target       string `json:"target,omitempty"`       // Host name or IP address
+	MaxHops      int    `json:"maxHops,omitempty"`      // 1-64
+	HopTimeoutMs int    `json:"hopTimeoutMs,omitempty"` // Per-probe wait, 100-10000
+}
+
+// hostnamePattern accepts host names and IPv4/IPv6 literals, and nothing
+// that could be mistaken for a command-line option.
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9.:\[\]][A-Za-z0-9.:\-_\[\]]*$`)
+
+// validate checks the settings ranges.
+func (s TracerouteSettings) validate() error {
+	if s.Target != "" && !hostnamePattern.MatchString(s.Target) {
+		return fmt.Errorf("invalid target %q", s.Target)
+	}
+	if s.MaxHops < 0 || s.MaxHops > 64 {
+		return errors.New("maxHops must be between 1 and 64")
+	}
+	if s.HopTimeoutMs != 0 && (s.HopTimeoutMs < 100 || s.HopTimeoutMs > 10000) {
+		return errors.New("hopTimeoutMs must be between 100 and 10000")
+	}
+	return nil
+}
+
+// withDefaults fills unset fields.
+func (s TracerouteSettings) withDefaults() TracerouteSettings {
+	if s.Target == "" {
+		s.Target = defaultTracerouteTarget
+	}
+	if s.MaxHops == 0 {
+		s.MaxHops = defaultTracerouteMaxHops
+	}
+	if s.HopTimeoutMs == 0 {
+		s.HopTimeoutMs = int(defaultTracerouteHopTimeout / time.Millisecond)
+	}
+	return s
+}
+
+// TracerouteHop is the result of one hop, emitted as a run event of kind
+// "hop" and collected in the run's data.
+type TracerouteHop struct {
+	Hop      int       `json:"hop"`
+	Address  string    `json:"address,omitempty"` // Empty if every probe timed out
+	RTTsMs   []float64 `json:"rttsMs"`            // Answered probes only
+	Timeouts int       `json:"timeouts"`
+	Reached  bool      `json:"reached"` // The target itself answered
+}
+
+// String formats the hop like the traceroute tools do.
+func (h TracerouteHop) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%2d  ", h.Hop)
+	if h.Address != "" {
+		b.WriteString(h.Address + "  ")
+	}
+	for _, rtt := range h.RTTsMs {
+		fmt.Fprintf(&b, "%.3f ms  ", rtt)
+	}
+	b.WriteString(strings.Repeat("*  ", h.Timeouts))
+	return strings.TrimRight(b.String(), " ")
+}
+
+// TracerouteResult is the structured output of the Traceroute check.
+type TracerouteResult struct {
+	Target  string          `json:"target"`
+	Address string          `json:"address"`
+	Method  string          `json:"method"` // icmp or the fallback tool's name
+	Hops    []TracerouteHop `json:"hops"`
+	Reached bool            `json:"reached"`
+}
+
+// checkTraceroute implements the Traceroute check: a native ICMP trace of
+// IPv4 targets where raw sockets are permitted, otherwise the OS traceroute
+// tool. IPv6 targets always use the tool.
+func checkTraceroute(r *checkRun) {
+	cfg := r.app.GetSettings().Traceroute.withDefaults()
+	hopTimeout := time.Duration(cfg.HopTimeoutMs) * time.Millisecond
+	r.header(fmt.Sprintf("Target : %s", cfg.Target), fmt.Sprintf("Limits : %d hops, %s per probe", cfg.MaxHops, hopTimeout))
+
+	addr, err := resolveTarget(r.ctx, cfg.Target)
+	if err != nil {
+		if !r.stopped() {
+			r.fail(fmt.Sprintf("Cannot resolve %s: %v", cfg.Target, err))
+		}
+		return
+	}
+
+	res := TracerouteResult{Target: cfg.Target, Address: addr.String(), Method: "icmp"}
+	hop := func(h TracerouteHop) {
+		res.Hops = append(res.Hops, h)
+		res.Reached = res.Reached || h.Reached
+		r.emit(RunEvent{Stream: streamSystem, Level: levelInfo, Text: h.String(), Kind: "hop", Data: h})
+	}
+
+	if addr.To4() == nil {
+		r.log(fmt.Sprintf("[INFO] The built-in trace supports IPv4 only; tracing %s with the system traceroute tool.", addr))
+		res.Method = tracerouteTool(addr, cfg, hopTimeout, hop, r)
+	} else if err := traceICMP(r.ctx, addr, cfg.MaxHops, hopTimeout, hop); errors.Is(err, errRawSocket) {
+		r.log("[INFO] Raw ICMP sockets need administrator/root rights; using the system traceroute tool.")
+		res.Method = tracerouteTool(addr, cfg, hopTimeout, hop, r)
+	} else if err != nil && !r.stopped() {
+		r.fail(fmt.Sprintf("Traceroute failed: %v", err))
+	}
+
+	r.setData("traceroute", res)
+	switch {
+	case r.stopped():
+	case res.Reached:
+		r.log(fmt.Sprintf("[OK] Reached %s in %d hops.", addr, len(res.Hops)))
+	default:
+		r.log(fmt.Sprintf("[WARN] %s was not reached within %d hops.", addr, cfg.MaxHops))
+	}
+}
+
+// resolveTarget returns the target's address, preferring IPv4.
+func resolveTarget(ctx context.Context, target string) (net.IP, error) {
+	if ip := net.ParseIP(strings.Trim(target, "[]")); ip != nil {
+		return ip, nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		if a.IP.To4() != nil {
+			return a.IP, nil
+		}
+	}
+	return addrs[0].IP, nil
+}
+
+// errRawSocket means the native trace is unavailable, usually for lack of
+// privileges, and the OS tool should be used instead.
+var errRawSocket = errors.New("raw ICMP socket unavailable")
+
+// ICMP message types used by the native trace.
+const (
+	icmpEchoReply      = 0
+	icmpUnreachable    = 3
+	icmpEcho           = 8
+	icmpTimeExceeded   = 11
+	icmpEchoHeaderSize = 8
+)
+
+// traceICMP sends ICMP echo requests with increasing TTLs over a raw
+// socket and reports each hop as it completes. It handles IPv4 only.
+func traceICMP(ctx context.Context, dst net.IP, maxHops int, hopTimeout time.Duration, report func(TracerouteHop)) error {
+	conn, err := listenICMP(dst)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errRawSocket, err)
+	}
+	id := nextICMPID()
+	buf := make([]byte, 1500)
+	seq := 0
+
+	for ttl := 1; ttl <= maxHops; ttl++ {
+		var ttlErr error
+		if err := rawConn.Control(func(fd uintptr) { ttlErr = setSocketTTL(fd, ttl) }); err != nil || ttlErr != nil {
+			return fmt.Errorf("%w: cannot set TTL: %v", errRawSocket, errors.Join(err, ttlErr))
+		}
+
+		h := TracerouteHop{Hop: ttl, RTTsMs: []float64{}}
+		for probe := 0; probe < tracerouteProbes; probe++ {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			seq++
+			sent := time.Now()
+			if _, err := conn.WriteTo(icmpEchoRequest(id, seq), &net.IPAddr{IP: dst}); err != nil {
+				return err
+			}
+
+			from, msgType, ok := awaitICMPReply(conn, buf, id, seq, sent.Add(hopTimeout))
+			if !ok {
+				h.Timeouts++
+				continue
+			}
+			h.RTTsMs = append(h.RTTsMs, float64(time.Since(sent).Microseconds())/1000)
+			if h.Address == "" {
+				h.Address = from.String()
+			}
+			if msgType == icmpEchoReply || (msgType == icmpUnreachable && from.Equal(dst)) {
+				h.Reached = true
+			}
+		}
+		report(h)
+		if h.Reached {
+			return nil
+		}
+	}
+	return nil
+}
+
+// listenICMP opens a raw ICMPv4 socket bound to the interface that routes
+// to dst: Windows only delivers ICMP errors to raw sockets bound to a
+// specific address.
+func listenICMP(dst net.IP) (*net.IPConn, error) {
+	local := "0.0.0.0"
+	if probe, err := net.Dial("udp4", net.JoinHostPort(dst.String(), "33434")); err == nil {
+		local = probe.LocalAddr().(*net.UDPAddr).IP.String()
+		probe.Close()
+	}
+	pc, err := net.ListenPacket("ip4:icmp", local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errRawSocket, err)
+	}
+	return pc.(*net.IPConn), nil
+}
+
+// icmpIDs counts the echo identifiers handed out by nextICMPID.
+var icmpIDs atomic.Uint32
+
+// nextICMPID returns the echo identifier for a new raw socket. Every raw
+// socket sees all ICMP traffic on the host, so a trace and concurrent pings
+// each need their own identifier to tell their replies apart.
+func nextICMPID() int {
+	return (os.Getpid() + int(icmpIDs.Add(1))) & 0xffff
+}
+
+// icmpEchoRequest builds an ICMP echo request with a small payload.
+func icmpEchoRequest(id, seq int) []byte {
+	msg := make([]byte, icmpEchoHeaderSize+32)
+	msg[0] = icmpEcho
+	binary.BigEndian.PutUint16(msg[4:], uint16(id))
+	binary.BigEndian.PutUint16(msg[6:], uint16(seq))
+	copy(msg[icmpEchoHeaderSize:], "CheckPoint traceroute probe.....")
+	binary.BigEndian.PutUint16(msg[2:], icmpChecksum(msg))
+	return msg
+}
+
+// icmpChecksum is the Internet checksum (RFC 1071).
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// awaitICMPReply reads until a reply to the given probe arrives or the
+// deadline passes. The raw socket sees every ICMP packet on the host, so
+// unrelated messages are skipped.
+func awaitICMPReply(conn *net.IPConn, buf []byte, id, seq int, deadline time.Time) (net.IP, int, bool) {
+	conn.SetReadDeadline(deadline)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, 0, false
+		}
+		msgType, ok := matchICMPReply(buf[:n], id, seq)
+		if ok {
+			return from.(*net.IPAddr).IP, msgType, true
+		}
+	}
+}
+
+// matchICMPReply reports whether an ICMP message answers the probe with the
+// given id and seq: an echo reply, or an error quoting the original request.
+func matchICMPReply(msg []byte, id, seq int) (int, bool) {
+	if len(msg) < icmpEchoHeaderSize {
+		return 0, false
+	}
+	probe := msg
+	switch msg[0] {
+	case icmpEchoReply:
+	case icmpTimeExceeded, icmpUnreachable:
+		// Header, then the original IPv4 header and the start of our echo request
+		quoted := msg[icmpEchoHeaderSize:]
+		if len(quoted) < 20 {
+			return 0, false
+		}
+		ihl := int(quoted[0]&0x0f) * 4
+		if len(quoted) < ihl+icmpEchoHeaderSize || quoted[ihl] != icmpEcho {
+			return 0, false
+		}
+		probe = quoted[ihl:]
+	default:
+		return 0, false
+	}
+	if int(binary.BigEndian.Uint16(probe[4:])) != id || int(binary.BigEndian.Uint16(probe[6:])) != seq {
+		return 0, false
+	}
+	return int(msg[0]), true
+}
+
+// tracerouteTool runs the OS traceroute tool, parsing hops as they are
+// printed. It returns the tool's name.
+func tracerouteTool(dst net.IP, cfg TracerouteSettings, hopTimeout time.Duration, report func(TracerouteHop), r *checkRun) string {
+	step := Step{Command: "traceroute", Quiet: true}
+	if runtime.GOOS == "windows" {
+		step.Command = "tracert"
+		step.Args = []string{"-d", "-h", strconv.Itoa(cfg.MaxHops), "-w", strconv.Itoa(cfg.HopTimeoutMs), dst.String()}
+	} else {
+		secs := strconv.Itoa(max(1, int(hopTimeout.Round(time.Second)/time.Second)))
+		step.Args = []string{"-n", "-m", strconv.Itoa(cfg.MaxHops), "-w", secs, "-q", strconv.Itoa(tracerouteProbes), dst.String()}
+		if dst.To4() == nil && runtime.GOOS == "darwin" {
+			step.Command = "traceroute6"
+		}
+	}
+	step.OnStdout = func(line string) {
+		if h, ok := parseTracerouteLine(line); ok {
+			h.Reached = h.Address != "" && net.ParseIP(h.Address).Equal(dst)
+			report(h)
+		}
+	}
+	r.runStep(step, false, nil)
+	return step.Command
+}
+
+// parseTracerouteLine parses one hop line of `traceroute -n` (Linux, macOS)
+// or `tracert -d` (Windows) output. Other lines are rejected.
+//
+//	3  10.0.0.1  5.123 ms  *  6.201 ms
+//	 3    12 ms    <1 ms     *     10.0.0.1
+//	 4     *        *        *     Request timed out.
+func parseTracerouteLine(line string) (TracerouteHop, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return TracerouteHop{}, false
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 1 {
+		return TracerouteHop{}, false
+	}
+
+	h := TracerouteHop{Hop: n, RTTsMs: []float64{}}
+	for i := 1; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "*":
+			h.Timeouts++
+		case i+1 < len(fields) && fields[i+1] == "ms":
+			if rtt, err := strconv.ParseFloat(strings.TrimPrefix(f, "<"), 64); err == nil {
+				h.RTTsMs = append(h.RTTsMs, rtt)
+			}
+			i++
+		case net.ParseIP(strings.Trim(f, "()[]")) != nil:
+			if h.Address == "" {
+				h.Address = strings.Trim(f, "()[]")
+			}
+		}
+	}
+	if len(h.RTTsMs) == 0 && h.Timeouts == 0 {
+		return TracerouteHop{}, false
+	}
+	return h, true
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTracerouteLine(t *testing.T) {
+	hop := func(n int, addr string, timeouts int, rtts ...float64) TracerouteHop {
+		return TracerouteHop{Hop: n, Address: addr, RTTsMs: append([]float64{}, rtts...), Timeouts: timeouts}
+	}
+	tests := []struct {
+		fixture string
+		want    []TracerouteHop
+	}{
+		{"traceroute_linux.txt", []TracerouteHop{
+			hop(1, "192.168.1.1", 0, 0.512, 0.471, 0.455),
+			hop(2, "100.64.0.1", 1, 4.812, 5.102),
+			hop(3, "", 3),
+			hop(4, "72.14.215.85", 0, 9.771, 9.540, 9.802), // The first responder is kept
+			hop(5, "8.8.8.8", 0, 10.214, 10.198, 10.176),
+		}},
+		{"traceroute6_darwin.txt", []TracerouteHop{
+			hop(1, "2001:db8:1::1", 0, 1.123, 0.987, 0.954),
+			hop(2, "", 3),
+			hop(3, "2001:4860:4860::8888", 0, 12.441, 11.902, 12.015),
+		}},
+		{"tracert_windows.txt", []TracerouteHop{
+			hop(1, "192.168.1.1", 0, 1, 1, 1),
+			hop(2, "100.64.0.1", 1, 5, 4),
+			hop(3, "", 3),
+			hop(4, "8.8.8.8", 0, 10, 9, 10),
+		}},
+		{"tracert6_windows.txt", []TracerouteHop{
+			hop(1, "2001:db8:1::1", 0, 1, 1, 1),
+			hop(2, "2001:4860:4860::8888", 0, 13, 12, 12),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			var got []TracerouteHop
+			for _, line := range strings.Split(readFixture(t, tt.fixture), "\n") {
+				if h, ok := parseTracerouteLine(line); ok {
+					got = append(got, h)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextICMPID(t *testing.T) {
+	seen := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		id := nextICMPID()
+		if id < 0 || id > 0xffff || seen[id] {
+			t.Fatalf("nextICMPID = %d, want a fresh 16-bit identifier", id)
+		}
+		seen[id] = true
+	}
+}
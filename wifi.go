@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// securityOpen is the Security of networks without encryption.
+const securityOpen = "open"
+
+// NetworkProfile is a saved network connection. Stored keys are never
+// read into it.
+type NetworkProfile struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`               // wifi, ethernet, vpn, ...
+	SSID        string `json:"ssid,omitempty"`     // Wi-Fi only
+	Security    string `json:"security,omitempty"` // e.g. WPA2-Personal or open; empty if unknown
+	Mode        string `json:"mode,omitempty"`     // Wi-Fi mode when not a client: ap (hotspot) or adhoc
+	AutoConnect bool   `json:"autoConnect"`
+	Hidden      bool   `json:"hidden,omitempty"`
+	Connected   bool   `json:"connected"`
+}
+
+// NetworkInventory is the structured output of the "Wi-Fi Profiles" check.
+type NetworkInventory struct {
+	Profiles  []NetworkProfile `json:"profiles"`
+	Connected []string         `json:"connected"` // SSIDs in use
+	Findings  []Finding        `json:"findings"`
+}
+
+// checkWiFi implements "Wi-Fi Profiles": it lists the saved network
+// profiles and the connected SSID (see readNetworkProfiles) and flags open
+// networks and hotspot profiles.
+func checkWiFi(r *checkRun) {
+	r.header("Status : Reading network profiles...")
+	inv, err := readNetworkProfiles(r)
+	if r.stopped() {
+		return
+	}
+	if err != nil {
+		r.fail(fmt.Sprintf("Cannot read network profiles: %v", err))
+		return
+	}
+	markConnected(inv.Profiles, inv.Connected)
+	sort.SliceStable(inv.Profiles, func(i, j int) bool {
+		a, b := inv.Profiles[i], inv.Profiles[j]
+		if a.Connected != b.Connected {
+			return a.Connected
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	if inv.Profiles == nil {
+		inv.Profiles = []NetworkProfile{}
+	}
+	if inv.Connected == nil {
+		inv.Connected = []string{}
+	}
+	inv.Findings = analyzeNetworkProfiles(inv.Profiles)
+
+	if len(inv.Connected) == 0 {
+		r.log("Connected : no Wi-Fi network")
+	} else {
+		r.log("Connected : " + strings.Join(inv.Connected, ", "))
+	}
+	r.log(formatNetworkProfiles(inv.Profiles))
+
+	r.setData("wifi", inv)
+	if len(inv.Findings) == 0 {
+		r.log("[OK] No open networks or hotspot profiles are saved.")
+	}
+	r.report(inv.Findings)
+}
+
+// markConnected sets Connected on the Wi-Fi profiles of the connected
+// SSIDs.
+func markConnected(profiles []NetworkProfile, connected []string) {
+	for i := range profiles {
+		for _, ssid := range connected {
+			if profiles[i].Type == "wifi" && profiles[i].SSID == ssid {
+				profiles[i].Connected = true
+			}
+		}
+	}
+}
+
+// formatNetworkProfiles renders the profiles as a table.
+func formatNetworkProfiles(profiles []NetworkProfile) string {
+	if len(profiles) == 0 {
+		return "No saved network profiles."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tSSID\tSECURITY\tAUTO\tNOTE")
+	for _, p := range profiles {
+		auto := "no"
+		if p.AutoConnect {
+			auto = "yes"
+		}
+		var notes []string
+		if p.Connected {
+			notes = append(notes, "connected")
+		}
+		if p.Hidden {
+			notes = append(notes, "hidden")
+		}
+		if p.Mode != "" {
+			notes = append(notes, p.Mode)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Name, p.Type, dash(p.SSID), dash(p.Security), auto, strings.Join(notes, ", "))
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// analyzeNetworkProfiles flags open Wi-Fi networks (an error while
+// connected, a warning when only saved) and profiles that turn this
+// machine into a hotspot or ad-hoc network.
+func analyzeNetworkProfiles(profiles []NetworkProfile) []Finding {
+	var findings []Finding
+	for _, p := range profiles {
+		if p.Type != "wifi" {
+			continue
+		}
+		switch {
+		case p.Mode != "":
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Profile %q shares a Wi-Fi network from this machine (%s mode).", p.Name, p.Mode)})
+		case p.Security == securityOpen && p.Connected:
+			findings = append(findings, Finding{Level: levelError,
+				Message: fmt.Sprintf("Connected to the open Wi-Fi network %q; anyone nearby can run a network with this name.", p.SSID)})
+		case p.Security == securityOpen:
+			findings = append(findings, Finding{Level: levelWarn,
+				Message: fmt.Sprintf("Open Wi-Fi network %q is saved; a rogue hotspot with this name is joined without a password.", p.SSID)})
+		}
+	}
+	return findings
+}
+
+// nmTypes maps NetworkManager connection types to profile types.
+var nmTypes = map[string]string{
+	"802-11-wireless": "wifi", "wifi": "wifi",
+	"802-3-ethernet": "ethernet", "ethernet": "ethernet",
+	"vpn": "vpn", "wireguard": "vpn",
+}
+
+// nmSecurity maps NetworkManager key-mgmt values to security names.
+var nmSecurity = map[string]string{
+	"none": "WEP", "ieee8021x": "WEP (802.1X)", "owe": "OWE",
+	"wpa-psk": "WPA-Personal", "sae": "WPA3-Personal",
+	"wpa-eap": "WPA-Enterprise", "wpa-eap-suite-b-192": "WPA3-Enterprise",
+}
+
+// nmSectionAliases maps the long keyfile section names of older
+// NetworkManager versions to the current ones.
+var nmSectionAliases = map[string]string{
+	"802-11-wireless":          "wifi",
+	"802-11-wireless-security": "wifi-security",
+}
+
+// parseNMConnection parses a NetworkManager keyfile
+// (/etc/NetworkManager/system-connections/*.nmconnection). Only the keys
+// below are read; secrets such as psk are skipped.
+//
+//	[connection]
+//	id=ExamHall
+//	type=wifi
+//	autoconnect=false
+//
+//	[wifi]
+//	mode=infrastructure
+//	ssid=ExamHall
+//
+//	[wifi-security]
+//	key-mgmt=wpa-psk
+func parseNMConnection(data string) (NetworkProfile, bool) {
+	values := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			if alias, ok := nmSectionAliases[section]; ok {
+				section = alias
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch k := section + "." + key; k {
+		case "connection.id", "connection.type", "connection.autoconnect",
+			"wifi.ssid", "wifi.mode", "wifi.hidden", "wifi-security.key-mgmt":
+			values[k] = value
+		}
+	}
+	if values["connection.id"] == "" {
+		return NetworkProfile{}, false
+	}
+
+	p := NetworkProfile{
+		Name:        values["connection.id"],
+		Type:        values["connection.type"],
+		AutoConnect: values["connection.autoconnect"] != "false",
+	}
+	if t, ok := nmTypes[p.Type]; ok {
+		p.Type = t
+	}
+	if p.Type != "wifi" {
+		return p, true
+	}
+	p.SSID = values["wifi.ssid"]
+	p.Hidden = values["wifi.hidden"] == "true"
+	if mode := values["wifi.mode"]; mode == "ap" || mode == "adhoc" {
+		p.Mode = mode
+	}
+	switch keyMgmt := values["wifi-security.key-mgmt"]; {
+	case keyMgmt == "":
+		p.Security = securityOpen
+	case nmSecurity[keyMgmt] != "":
+		p.Security = nmSecurity[keyMgmt]
+	default:
+		p.Security = keyMgmt
+	}
+	return p, true
+}
+
+// splitNmcliFields splits a line of `nmcli -t` output, where ":" separates
+// fields and "\:" is a literal colon.
+func splitNmcliFields(line string) []string {
+	var fields []string
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			b.WriteByte(line[i])
+		case line[i] == ':':
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(line[i])
+		}
+	}
+	return append(fields, b.String())
+}
+
+// parseNmcliConnections parses `nmcli -t -f NAME,TYPE,AUTOCONNECT
+// connection show`, used when the keyfiles are not readable. The SSID is
+// not listed; NetworkManager names new Wi-Fi profiles after it.
+func parseNmcliConnections(data string) []NetworkProfile {
+	var profiles []NetworkProfile
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		f := splitNmcliFields(scanner.Text())
+		if len(f) < 3 || f[0] == "" {
+			continue
+		}
+		p := NetworkProfile{Name: f[0], Type: f[1], AutoConnect: f[2] == "yes"}
+		if t, ok := nmTypes[p.Type]; ok {
+			p.Type = t
+		}
+		if p.Type == "wifi" {
+			p.SSID = p.Name
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// parseNmcliActiveSSIDs parses `nmcli -t -f ACTIVE,SSID device wifi list`,
+// returning the SSIDs marked active.
+func parseNmcliActiveSSIDs(data string) []string {
+	var ssids []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		if f := splitNmcliFields(scanner.Text()); len(f) >= 2 && f[0] == "yes" && f[1] != "" {
+			ssids = append(ssids, f[1])
+		}
+	}
+	return ssids
+}
+
+// netshValuePattern matches "    Key     : value" lines of netsh output.
+var netshValuePattern = regexp.MustCompile(`^\s*([^:]+?)\s+:\s*(.*)$`)
+
+// netshValues returns the "Key : value" pairs of netsh output. Later keys
+// with the same name overwrite earlier ones, and "Key Content" (only
+// printed with key=clear, which is never passed) is dropped.
+func netshValues(data string) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		if m := netshValuePattern.FindStringSubmatch(scanner.Text()); m != nil && m[1] != "Key Content" {
+			values[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return values
+}
+
+// parseNetshProfileNames parses `netsh wlan show profiles`:
+//
+//	User profiles
+//	-------------
+//	    All User Profile     : ExamHall
+func parseNetshProfileNames(data string) []string {
+	var names []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		m := netshValuePattern.FindStringSubmatch(scanner.Text())
+		if m != nil && strings.HasSuffix(m[1], "Profile") && m[2] != "" {
+			names = append(names, strings.TrimSpace(m[2]))
+		}
+	}
+	return names
+}
+
+// parseNetshProfile parses `netsh wlan show profile name=<name>` (without
+// key=clear):
+//
+//	Name                   : ExamHall
+//	Connection mode        : Connect automatically
+//	SSID name              : "ExamHall"
+//	Authentication         : WPA2-Personal
+func parseNetshProfile(name, data string) NetworkProfile {
+	v := netshValues(data)
+	p := NetworkProfile{Name: name, Type: "wifi", SSID: strings.Trim(v["SSID name"], `"`), AutoConnect: v["Connection mode"] == "Connect automatically"}
+	if p.SSID == "" {
+		p.SSID = name
+	}
+	p.Hidden = strings.Contains(v["Network broadcast"], "even if")
+	switch auth := v["Authentication"]; {
+	case auth == "Open" && (v["Cipher"] == "None" || v["Cipher"] == ""):
+		p.Security = securityOpen
+	case auth == "Open":
+		p.Security = "WEP"
+	default:
+		p.Security = auth
+	}
+	return p
+}
+
+// parseNetshInterfaces returns the SSIDs of connected interfaces in
+// `netsh wlan show interfaces`, one block per interface:
+//
+//	Name                   : Wi-Fi
+//	State                  : connected
+//	SSID                   : ExamHall
+func parseNetshInterfaces(data string) []string {
+	var ssids []string
+	var state, ssid string
+	flush := func() {
+		if state == "connected" && ssid != "" {
+			ssids = append(ssids, ssid)
+		}
+		state, ssid = "", ""
+	}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		m := netshValuePattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "Name":
+			flush()
+		case "State":
+			state = strings.TrimSpace(m[2])
+		case "SSID":
+			ssid = strings.TrimSpace(m[2])
+		}
+	}
+	flush()
+	return ssids
+}
+
+// parseWiFiDevice returns the device of the Wi-Fi port in `networksetup
+// -listallhardwareports`:
+//
+//	Hardware Port: Wi-Fi
+//	Device: en0
+func parseWiFiDevice(data string) string {
+	port := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if v, ok := strings.CutPrefix(line, "Hardware Port: "); ok {
+			port = v
+		} else if v, ok := strings.CutPrefix(line, "Device: "); ok && (port == "Wi-Fi" || port == "AirPort") {
+			return v
+		}
+	}
+	return ""
+}
+
+// parsePreferredNetworks parses `networksetup -listpreferredwirelessnetworks
+// <device>`: a header followed by one indented SSID per line.
+func parsePreferredNetworks(data string) []NetworkProfile {
+	var profiles []NetworkProfile
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, " ") {
+			continue
+		}
+		if ssid := strings.TrimSpace(line); ssid != "" {
+			profiles = append(profiles, NetworkProfile{Name: ssid, Type: "wifi", SSID: ssid, AutoConnect: true})
+		}
+	}
+	return profiles
+}
+
+// parseAirportNetwork parses `networksetup -getairportnetwork <device>`:
+// "Current Wi-Fi Network: ExamHall", or a sentence when not associated.
+func parseAirportNetwork(data string) []string {
+	for _, prefix := range []string{"Current Wi-Fi Network: ", "Current AirPort Network: "} {
+		if ssid, ok := strings.CutPrefix(strings.TrimSpace(data), prefix); ok && ssid != "" {
+			return []string{ssid}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import "strings"
+
+// readNetworkProfiles lists the preferred wireless networks and the
+// current network of the Wi-Fi device with networksetup. macOS does not
+// print the security of saved networks. A failing command is reported as
+// a failed step.
+func readNetworkProfiles(r *checkRun) (NetworkInventory, error) {
+	ports := r.capture(Step{Command: "networksetup", Args: []string{"-listallhardwareports"}, Quiet: true})
+	device := parseWiFiDevice(strings.Join(ports, "\n"))
+	if device == "" {
+		r.log("[INFO] No Wi-Fi device found.")
+		return NetworkInventory{}, nil
+	}
+	preferred := r.capture(Step{Command: "networksetup", Args: []string{"-listpreferredwirelessnetworks", device}, Quiet: true})
+	current := r.capture(Step{Command: "networksetup", Args: []string{"-getairportnetwork", device}, Quiet: true})
+	return NetworkInventory{
+		Profiles:  parsePreferredNetworks(strings.Join(preferred, "\n")),
+		Connected: parseAirportNetwork(strings.Join(current, "\n")),
+	}, nil
+}
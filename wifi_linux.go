@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// nmConnectionsDir holds the NetworkManager keyfiles, readable by root only.
+const nmConnectionsDir = "/etc/NetworkManager/system-connections"
+
+// readNetworkProfiles parses the NetworkManager keyfiles, falling back to
+// `nmcli connection show` when they are not readable or yield no profile
+// (profiles kept elsewhere, such as in /run or by the ifcfg plugin), and
+// takes the connected SSID from the cached `nmcli device wifi list`
+// results; a rescan would stall the check for several seconds.
+func readNetworkProfiles(r *checkRun) (NetworkInventory, error) {
+	var inv NetworkInventory
+	entries, err := os.ReadDir(nmConnectionsDir)
+	readable := err == nil || errors.Is(err, os.ErrNotExist)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(nmConnectionsDir, entry.Name()))
+		if err != nil {
+			readable = false
+			break
+		}
+		if p, ok := parseNMConnection(string(data)); ok {
+			inv.Profiles = append(inv.Profiles, p)
+		}
+	}
+	if !hasCommand("nmcli") {
+		return inv, nil
+	}
+
+	if !readable || len(inv.Profiles) == 0 {
+		if readable {
+			r.log("[INFO] No NetworkManager keyfiles found; listing profiles with nmcli (no security details).")
+		} else {
+			r.log("[INFO] NetworkManager profiles are readable by root only; listing them with nmcli (no security details).")
+		}
+		out := r.capture(Step{Command: "nmcli", Args: []string{"-t", "-f", "NAME,TYPE,AUTOCONNECT", "connection", "show"}, Quiet: true})
+		inv.Profiles = parseNmcliConnections(strings.Join(out, "\n"))
+	}
+	out := r.capture(Step{Command: "nmcli", Args: []string{"-t", "-f", "ACTIVE,SSID", "device", "wifi", "list", "--rescan", "no"}, Quiet: true})
+	inv.Connected = parseNmcliActiveSSIDs(strings.Join(out, "\n"))
+	return inv, nil
+}
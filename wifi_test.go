@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNMConnection(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    NetworkProfile
+	}{
+		{"nm_examhall.nmconnection", NetworkProfile{Name: "ExamHall", Type: "wifi", SSID: "ExamHall", Security: "WPA-Personal"}},
+		// Older NetworkManager versions use the long section and type names
+		{"nm_cafe.nmconnection", NetworkProfile{Name: "Cafe Guest", Type: "wifi", SSID: "Cafe Guest", Security: securityOpen, AutoConnect: true, Hidden: true}},
+		{"nm_hotspot.nmconnection", NetworkProfile{Name: "Hotspot", Type: "wifi", SSID: "laptop-hotspot", Security: "WPA3-Personal", Mode: "ap"}},
+		{"nm_wired.nmconnection", NetworkProfile{Name: "Wired connection 1", Type: "ethernet", AutoConnect: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, ok := parseNMConnection(readFixture(t, tt.fixture))
+			if !ok || got != tt.want {
+				t.Errorf("parseNMConnection = %+v, %v; want %+v", got, ok, tt.want)
+			}
+		})
+	}
+	if _, ok := parseNMConnection("[wifi]\nssid=NoID\n"); ok {
+		t.Error("parseNMConnection accepted a keyfile without connection.id")
+	}
+}
+
+func TestParseNmcliConnections(t *testing.T) {
+	want := []NetworkProfile{
+		{Name: "ExamHall", Type: "wifi", SSID: "ExamHall"},
+		{Name: "Wired connection 1", Type: "ethernet", AutoConnect: true},
+		{Name: "Office:5GHz", Type: "wifi", SSID: "Office:5GHz", AutoConnect: true},
+		{Name: "wg0", Type: "vpn", AutoConnect: true},
+		{Name: "lo", Type: "loopback"},
+	}
+	if got := parseNmcliConnections(readFixture(t, "nmcli_connections.txt")); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNmcliConnections = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseNmcliActiveSSIDs(t *testing.T) {
+	want := []string{"Office:5GHz"}
+	if got := parseNmcliActiveSSIDs(readFixture(t, "nmcli_wifi_list.txt")); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNmcliActiveSSIDs = %q, want %q", got, want)
+	}
+}
+
+func TestParseNetshProfile(t *testing.T) {
+	tests := []struct {
+		fixture string
+		name    string
+		want    NetworkProfile
+	}{
+		{"netsh_profile_windows.txt", "ExamHall", NetworkProfile{Name: "ExamHall", Type: "wifi", SSID: "ExamHall", Security: "WPA2-Personal", AutoConnect: true, Hidden: true}},
+		{"netsh_profile_open_windows.txt", "Airport Free", NetworkProfile{Name: "Airport Free", Type: "wifi", SSID: "Airport Free", Security: securityOpen}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			if got := parseNetshProfile(tt.name, readFixture(t, tt.fixture)); got != tt.want {
+				t.Errorf("parseNetshProfile = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectedSSIDParsers(t *testing.T) {
+	if got, want := parseNetshInterfaces(readFixture(t, "netsh_interfaces_windows.txt")), []string{"ExamHall"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNetshInterfaces = %q, want %q", got, want)
+	}
+	if got, want := parseAirportNetwork("Current Wi-Fi Network: ExamHall\n"), []string{"ExamHall"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAirportNetwork = %q, want %q", got, want)
+	}
+	if got := parseAirportNetwork("You are not associated with an AirPort network.\n"); got != nil {
+		t.Errorf("parseAirportNetwork when not associated = %q, want none", got)
+	}
+}
+
+func TestParsePreferredNetworks(t *testing.T) {
+	want := []NetworkProfile{
+		{Name: "ExamHall", Type: "wifi", SSID: "ExamHall", AutoConnect: true},
+		{Name: "Cafe Guest", Type: "wifi", SSID: "Cafe Guest", AutoConnect: true},
+		{Name: "eduroam", Type: "wifi", SSID: "eduroam", AutoConnect: true},
+	}
+	if got := parsePreferredNetworks(readFixture(t, "networksetup_preferred_darwin.txt")); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePreferredNetworks = %+v, want %+v", got, want)
+	}
+}
+
+// TestNetworkProfilesOmitSecrets proves that stored keys in the fixtures
+// never reach a NetworkProfile, and so neither the log nor the results.
+func TestNetworkProfilesOmitSecrets(t *testing.T) {
+	tests := []struct {
+		fixture string
+		secret  string
+		parse   func(string) NetworkProfile
+	}{
+		{"nm_examhall.nmconnection", "Hunter2-ExamHall-Passphrase", func(data string) NetworkProfile { p, _ := parseNMConnection(data); return p }},
+		{"nm_hotspot.nmconnection", "Hotspot-Secret-Passphrase", func(data string) NetworkProfile { p, _ := parseNMConnection(data); return p }},
+		{"netsh_profile_windows.txt", "Hunter2-Netsh-KeyContent", func(data string) NetworkProfile { return parseNetshProfile("ExamHall", data) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data := readFixture(t, tt.fixture)
+			if !strings.Contains(data, tt.secret) {
+				t.Fatalf("fixture does not contain the secret %q", tt.secret)
+			}
+			p := tt.parse(data)
+			out, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(out), tt.secret) {
+				t.Errorf("profile JSON contains the secret: %s", out)
+			}
+			if table := formatNetworkProfiles([]NetworkProfile{p}); strings.Contains(table, tt.secret) {
+				t.Errorf("profile table contains the secret:\n%s", table)
+			}
+		})
+	}
+	if v := netshValues(readFixture(t, "netsh_profile_windows.txt")); v["Key Content"] != "" {
+		t.Errorf("netshValues kept Key Content %q", v["Key Content"])
+	}
+}
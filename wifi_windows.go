@@ -0,0 +1,25 @@
+package main
+
+import "strings"
+
+// readNetworkProfiles lists the WLAN profiles with their security (never
+// with key=clear) and the connected SSIDs. The listing runs through
+// PowerShell so machines without the WLAN service report no profiles
+// rather than a failed step.
+func readNetworkProfiles(r *checkRun) (NetworkInventory, error) {
+	var inv NetworkInventory
+	listing := powerShellStep("", "netsh wlan show profiles; exit 0")
+	listing.Quiet = true
+	for _, name := range parseNetshProfileNames(strings.Join(r.capture(listing), "\n")) {
+		if r.stopped() {
+			return inv, nil
+		}
+		out := r.capture(Step{Command: "netsh", Args: []string{"wlan", "show", "profile", "name=" + name}, Quiet: true})
+		inv.Profiles = append(inv.Profiles, parseNetshProfile(name, strings.Join(out, "\n")))
+	}
+
+	interfaces := powerShellStep("", "netsh wlan show interfaces; exit 0")
+	interfaces.Quiet = true
+	inv.Connected = parseNetshInterfaces(strings.Join(r.capture(interfaces), "\n"))
+	return inv, nil
+}